@@ -0,0 +1,400 @@
+package chantest
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// An Asserter makes assertions against a TestingT, waiting for each of them
+// for its Before duration, as measured by its Clock.
+//
+// An Asserter is itself a TestingT, so it can be passed to any function in this
+// package that takes one, which then uses the Asserter's configuration.
+type Asserter struct {
+	// t is never an Asserter.
+	t TestingT
+	config
+}
+
+type config struct {
+	d     Before
+	clock Clock
+	// timers, if set, reuses timers from the system clock.
+	timers *timerPool
+	// registry holds the channels passed to Register.
+	registry *registry
+	strict   bool
+	// equalOpts configures every comparison of a received value.
+	equalOpts []EqualOption
+	// seed, if set, seeds Rand.
+	seed *int64
+	// perturb is the most times wrapped channels yield around each value.
+	perturb int
+	// retry, if set, is how positive assertions retry after a timeout.
+	retry   *retryPolicy
+	polling PollOptions
+	// invalid, if set, is why an option couldn't apply, for New to fail the
+	// test with.
+	invalid string
+}
+
+func defaultConfig() config {
+	return config{
+		d:     Default,
+		clock: systemClock{},
+	}
+}
+
+// An Option configures an Asserter.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (f optionFunc) apply(c *config) { f(c) }
+
+// apply makes a Before an Option that sets the Asserter's Before duration.
+func (d Before) apply(c *config) { c.d = d }
+
+// WithClock sets the Clock an Asserter's timeouts are measured with. The
+// default is the system clock.
+func WithClock(clock Clock) Option {
+	return optionFunc(func(c *config) { c.clock = clock })
+}
+
+// Strict makes an Asserter keep track of the values its assertions receive, so
+// that Verify can fail if registered channels carried any others.
+func Strict() Option {
+	return optionFunc(func(c *config) { c.strict = true })
+}
+
+// WithEqual makes every assertion that compares a received value with an
+// expected one do so as configured by opts, as AssertRecvEqual does with its
+// own options.
+func WithEqual(opts ...EqualOption) Option {
+	return optionFunc(func(c *config) { c.equalOpts = append(c.equalOpts[:len(c.equalOpts):len(c.equalOpts)], opts...) })
+}
+
+// apply makes an Asserter an Option that sets all of its configuration, so
+// that channels wrapped with Instrument or Chaos in a Stress body can share
+// the body's.
+func (a *Asserter) apply(c *config) { *c = a.config }
+
+// Perturb makes wrapped channels, like Instrumented and Chaotic ones, call
+// runtime.Gosched a random number of times, up to maxYields, before and after
+// relaying each value, to vary how the goroutines around them interleave
+// without changing the code under test.
+func Perturb(maxYields int) Option {
+	return optionFunc(func(c *config) { c.perturb = maxYields })
+}
+
+// WithSeed sets the seed of the generators returned by Rand, so that a
+// randomized scenario can be reproduced.
+func WithSeed(seed int64) Option {
+	return optionFunc(func(c *config) { c.seed = &seed })
+}
+
+// New returns an Asserter for t, waiting for Default with the system clock
+// unless otherwise set by opts. A Before is itself an Option.
+//
+// If t is already an Asserter, the new one starts with its configuration.
+//
+// The Asserter reuses its system timers across assertions, so it shouldn't be
+// shared between testing/synctest bubbles.
+func New(t TestingT, opts ...Option) *Asserter {
+	a := asserterFor(t)
+	c := a.config
+	for _, opt := range opts {
+		opt.apply(&c)
+	}
+	if c.timers == nil {
+		c.timers = &timerPool{}
+	}
+	if c.registry == nil {
+		c.registry = &registry{}
+	}
+	if c.invalid != "" {
+		msg := c.invalid
+		c.invalid = ""
+		a.t.Helper()
+		a.t.Fatal(msg)
+	}
+	return &Asserter{t: a.t, config: c}
+}
+
+// asserterFor returns t if it's already an Asserter, or an Asserter wrapping t
+// with the default configuration otherwise.
+func asserterFor(t TestingT) *Asserter {
+	if a, ok := t.(*Asserter); ok {
+		return a
+	}
+	return &Asserter{t: t, config: defaultConfig()}
+}
+
+// on returns an Asserter for t waiting for d. If t is an Asserter, the
+// rest of its configuration is kept.
+func (d Before) on(t TestingT) *Asserter {
+	a := asserterFor(t)
+	if a.d == d {
+		return a
+	}
+	c := a.config
+	c.d = d
+	return &Asserter{t: a.t, config: c}
+}
+
+// Unwrap returns the TestingT an Asserter wraps, or t itself if it isn't one.
+//
+// Helper marks its direct caller, so functions that take a TestingT, like the
+// ones chantestgen generates, call Helper on the unwrapped one, rather than on
+// the Asserter, whose Helper method would mark itself instead.
+func Unwrap(t TestingT) TestingT {
+	return unwrap(t)
+}
+
+func unwrap(t TestingT) TestingT {
+	if a, ok := t.(*Asserter); ok {
+		return a.t
+	}
+	return t
+}
+
+// timeout returns a channel that fires once the Asserter's Before duration
+// elapses, and a function to call once done with it.
+func (a *Asserter) timeout() (<-chan time.Time, func()) {
+	tm := a.startTimer()
+	return tm.C, tm.stop
+}
+
+// startTimer is like timeout, without allocating if the Asserter reuses
+// timers.
+func (a *Asserter) startTimer() timer {
+	d := time.Duration(a.d)
+	if _, ok := a.clock.(systemClock); ok && a.timers != nil {
+		return a.timers.start(d)
+	}
+	c, stop := startTimer(a.clock, d)
+	return timer{C: c, stopFunc: stop}
+}
+
+// Seed returns the Asserter's seed, as set with WithSeed, or 0 if there's
+// none.
+func (a *Asserter) Seed() int64 {
+	if a.seed == nil {
+		return 0
+	}
+	return *a.seed
+}
+
+// Rand returns a new pseudo-random generator seeded with the Asserter's seed,
+// or, if it has none, with the current time.
+func (a *Asserter) Rand() *rand.Rand {
+	return a.rand(0)
+}
+
+// rand is like Rand, with offset added to the seed, for generators that must
+// differ from each other.
+func (c config) rand(offset int64) *rand.Rand {
+	seed := time.Now().UnixNano()
+	if c.seed != nil {
+		seed = *c.seed
+	}
+	return rand.New(rand.NewSource(seed + offset))
+}
+
+// perturber returns a function that yields as set by Perturb, with a
+// generator like rand's.
+func (c config) perturber(offset int64) func() {
+	if c.perturb <= 0 {
+		return func() {}
+	}
+	r := c.rand(offset)
+	return func() {
+		for i := r.Intn(c.perturb + 1); i > 0; i-- {
+			runtime.Gosched()
+		}
+	}
+}
+
+// Helper calls Helper on the Asserter's TestingT.
+func (a *Asserter) Helper() {
+	a.t.Helper()
+}
+
+// Fatal calls Fatal on the Asserter's TestingT.
+func (a *Asserter) Fatal(args ...interface{}) {
+	a.t.Helper()
+	a.t.Fatal(args...)
+}
+
+// Expect is Before.Expect with the Asserter's configuration.
+func (a *Asserter) Expect(do func()) {
+	a.t.Helper()
+	a.d.Expect(a, do)
+}
+
+// ExpectOrdered is Before.ExpectOrdered with the Asserter's configuration.
+func (a *Asserter) ExpectOrdered(do ...func()) {
+	a.t.Helper()
+	a.d.ExpectOrdered(a, do...)
+}
+
+// ExpectBlocked is Before.ExpectBlocked with the Asserter's configuration.
+func (a *Asserter) ExpectBlocked(do func()) {
+	a.t.Helper()
+	a.d.ExpectBlocked(a, do)
+}
+
+// AssertUnblockedBy is Before.AssertUnblockedBy with the Asserter's configuration.
+func (a *Asserter) AssertUnblockedBy(do, release func()) {
+	a.t.Helper()
+	a.d.AssertUnblockedBy(a, do, release)
+}
+
+// AssertBlockedOn is Before.AssertBlockedOn with the Asserter's configuration.
+func (a *Asserter) AssertBlockedOn(op Op, fn interface{}, msgAndArgs ...interface{}) {
+	a.t.Helper()
+	a.d.AssertBlockedOn(a, op, fn, msgAndArgs...)
+}
+
+// AssertNotReceiving is Before.AssertNotReceiving with the Asserter's
+// configuration.
+func (a *Asserter) AssertNotReceiving(fn interface{}, msgAndArgs ...interface{}) {
+	a.t.Helper()
+	a.d.AssertNotReceiving(a, fn, msgAndArgs...)
+}
+
+// AssertRecv is Before.AssertRecv with the Asserter's configuration.
+func (a *Asserter) AssertRecv(ch interface{}, msgAndArgs ...interface{}) interface{} {
+	a.t.Helper()
+	return a.d.AssertRecv(a, ch, msgAndArgs...)
+}
+
+// AssertNoRecv is Before.AssertNoRecv with the Asserter's configuration.
+func (a *Asserter) AssertNoRecv(ch interface{}, msgAndArgs ...interface{}) interface{} {
+	a.t.Helper()
+	return a.d.AssertNoRecv(a, ch, msgAndArgs...)
+}
+
+// AssertRecvAny is Before.AssertRecvAny with the Asserter's configuration.
+func (a *Asserter) AssertRecvAny(chs ...interface{}) (index int, v interface{}) {
+	a.t.Helper()
+	return a.d.AssertRecvAny(a, chs...)
+}
+
+// AssertRecvEach is Before.AssertRecvEach with the Asserter's configuration.
+func (a *Asserter) AssertRecvEach(chs ...interface{}) []interface{} {
+	a.t.Helper()
+	return a.d.AssertRecvEach(a, chs...)
+}
+
+// AssertRecvInOrder is Before.AssertRecvInOrder with the Asserter's
+// configuration.
+func (a *Asserter) AssertRecvInOrder(chs ...interface{}) []interface{} {
+	a.t.Helper()
+	return a.d.AssertRecvInOrder(a, chs...)
+}
+
+// AssertSend is Before.AssertSend with the Asserter's configuration.
+func (a *Asserter) AssertSend(ch, v interface{}, msgAndArgs ...interface{}) {
+	a.t.Helper()
+	a.d.AssertSend(a, ch, v, msgAndArgs...)
+}
+
+// AssertNoSend is Before.AssertNoSend with the Asserter's configuration.
+func (a *Asserter) AssertNoSend(ch, v interface{}, msgAndArgs ...interface{}) {
+	a.t.Helper()
+	a.d.AssertNoSend(a, ch, v, msgAndArgs...)
+}
+
+// A Goroutine is a body function started by Go.
+type Goroutine struct {
+	t    TestingT
+	done chan struct{}
+
+	// failure is set before done is closed.
+	failure *string
+
+	reportOnce sync.Once
+}
+
+// Go runs body in a new goroutine, with an Asserter whose failures are
+// captured instead of being reported from that goroutine, which *testing.T
+// doesn't allow. Panics in body are captured too.
+//
+// Captured failures are reported to t by Goroutine.Wait. If t has a Cleanup
+// method, like *testing.T, Wait is also registered with it.
+//
+// If t is an Asserter, the body's Asserter has the same configuration.
+func Go(t TestingT, body func(a *Asserter)) *Goroutine {
+	parent := asserterFor(t)
+	parent.t.Helper()
+	g := &Goroutine{t: parent.t, done: make(chan struct{})}
+	a := &Asserter{
+		t:      goroutineT{g},
+		config: parent.config,
+	}
+	go func() {
+		defer close(g.done)
+		defer func() {
+			if r := recover(); r != nil {
+				g.fail(fmt.Sprintf("panic: %v\n%s", r, debug.Stack()))
+			}
+		}()
+		body(a)
+	}()
+	if c, ok := parent.t.(cleanuper); ok {
+		c.Cleanup(g.Wait)
+	}
+	return g
+}
+
+// Done returns a channel that is closed when the body returns.
+func (g *Goroutine) Done() <-chan struct{} {
+	return g.done
+}
+
+// Wait blocks until the body returns, and then reports its failure, if any, as
+// a Fatal call on the TestingT passed to Go. The failure is only reported once,
+// no matter how many times Wait is called.
+//
+// Wait doesn't time out; use Expect(t, g.Wait) for that.
+func (g *Goroutine) Wait() {
+	g.t.Helper()
+	<-g.done
+	if g.failure == nil {
+		return
+	}
+	g.reportOnce.Do(func() {
+		g.t.Helper()
+		g.t.Fatal(*g.failure)
+	})
+}
+
+func (g *Goroutine) fail(msg string) {
+	if g.failure == nil {
+		g.failure = &msg
+	}
+}
+
+// goroutineT is the TestingT of the Asserter passed to a Go body.
+type goroutineT struct {
+	g *Goroutine
+}
+
+func (t goroutineT) Helper() {}
+
+// Fatal records the failure and stops the body's goroutine.
+func (t goroutineT) Fatal(args ...interface{}) {
+	t.g.fail(fmt.Sprint(args...))
+	runtime.Goexit()
+}
+
+type cleanuper interface {
+	Cleanup(func())
+}
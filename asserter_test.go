@@ -0,0 +1,75 @@
+package chantest
+
+import "testing"
+
+func TestGo(t *testing.T) {
+	ch := make(chan int, 1)
+	ch <- 42
+
+	assertPasses(t, func(t TestingT) {
+		got := make(chan interface{}, 1)
+		Go(t, func(a *Asserter) {
+			got <- a.AssertRecv(ch)
+		}).Wait()
+		if v := <-got; v != 42 {
+			t.Fatal("unexpected value", v)
+		}
+	})
+
+	assertFails(t, "timeout waiting for channel send or receive", func(t TestingT) {
+		g := Go(t, func(a *Asserter) {
+			a = New(a, short)
+			a.AssertRecv(ch)
+			panic("unreachable")
+		})
+		g.Wait()
+	})
+
+	assertFails(t, "panic: oops", func(t TestingT) {
+		Go(t, func(a *Asserter) {
+			panic("oops")
+		}).Wait()
+	})
+}
+
+func TestGoAsserterPassesAsTestingT(t *testing.T) {
+	assertFails(t, "unexpected return", func(t TestingT) {
+		Go(New(t, short), func(a *Asserter) {
+			ExpectBlocked(a, func() {})
+		}).Wait()
+	})
+}
+
+func TestGoReportsOnCleanup(t *testing.T) {
+	for _, asserter := range []bool{false, true} {
+		ct := &cleanupT{}
+		var parent TestingT = ct
+		if asserter {
+			parent = New(ct)
+		}
+		Go(parent, func(a *Asserter) {
+			a.Fatal("failed in goroutine")
+		})
+		if len(ct.cleanups) != 1 {
+			t.Fatalf("Asserter %v: expected 1 cleanup, got %d", asserter, len(ct.cleanups))
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			ct.cleanups[0]()
+		}()
+		<-done
+		if !ct.failed || ct.msg != "failed in goroutine" {
+			t.Fatalf("Asserter %v: unexpected cleanup failure: %q", asserter, ct.msg)
+		}
+	}
+}
+
+type cleanupT struct {
+	fakeT
+	cleanups []func()
+}
+
+func (t *cleanupT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
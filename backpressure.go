@@ -0,0 +1,34 @@
+package chantest
+
+import "fmt"
+
+// FillToCapacity sends zero values to ch until its buffer is full, and returns
+// how many it sent. It sends no more than the free slots there were to begin
+// with, and fails the test if a send blocks, if the buffer then isn't full, or
+// if ch accepts another value before the timeout: the last two mean something
+// is receiving from it.
+//
+// Useful for asserting what a producer does when it can't send.
+func FillToCapacity[T any](t TestingT, ch chan<- T, msgAndArgs ...interface{}) int {
+	a := asserterFor(t)
+	a.t.Helper()
+	var zero T
+	n := 0
+	for free := cap(ch) - len(ch); n < free; {
+		select {
+		case ch <- zero:
+			n++
+		default:
+			a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("send blocked with %d of %d buffer slots used", len(ch), cap(ch)), msgAndArgs...))
+			return n
+		}
+	}
+	if l := len(ch); l < cap(ch) {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("only %d of %d buffer slots used after sending %d values; something is receiving from it", l, cap(ch), n), msgAndArgs...))
+		return n
+	}
+	if send(a, ch, zero) {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("channel accepted more values than its capacity of %d; something is receiving from it", cap(ch)), msgAndArgs...))
+	}
+	return n
+}
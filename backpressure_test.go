@@ -0,0 +1,42 @@
+package chantest
+
+import "testing"
+
+func TestFillToCapacity(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	var n int
+	assertPasses(t, func(t TestingT) {
+		n = FillToCapacity(New(t, short), ch)
+	})
+	if n != 2 || len(ch) != 3 {
+		t.Fatalf("sent %d, len %d", n, len(ch))
+	}
+
+	drained := make(chan int, 1)
+	go func() {
+		for range drained {
+		}
+	}()
+	assertFails(t, "something is receiving from it", func(t TestingT) {
+		FillToCapacity(New(t, short), drained)
+	})
+
+	// A receiver that keeps draining the buffer mustn't keep FillToCapacity
+	// sending forever.
+	draining := make(chan int, 3)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-draining:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	assertFails(t, "something is receiving from it", func(t TestingT) {
+		FillToCapacity(New(t, short), draining)
+	})
+}
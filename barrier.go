@@ -0,0 +1,65 @@
+package chantest
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A Barrier makes a fixed number of goroutines wait for each other at some
+// point in a test.
+//
+// Once all participants have arrived, the Barrier resets, so it can be reused
+// for the next round.
+type Barrier struct {
+	n int
+
+	mu      sync.Mutex
+	arrived int
+	release chan struct{}
+}
+
+// NewBarrier returns a Barrier for n participants.
+func NewBarrier(n int) *Barrier {
+	return &Barrier{n: n, release: make(chan struct{})}
+}
+
+// Arrive blocks until all participants have called Arrive, and fails the test
+// if that doesn't happen very quickly. A participant that times out leaves, so
+// the round still needs as many more arrivals as before it arrived.
+func (b *Barrier) Arrive(t TestingT) {
+	a := asserterFor(t)
+	a.t.Helper()
+	b.mu.Lock()
+	release := b.arrive()
+	b.mu.Unlock()
+
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case <-release:
+	case <-timeout:
+		b.mu.Lock()
+		if b.release != release {
+			// The round was released as the timeout fired.
+			b.mu.Unlock()
+			return
+		}
+		arrived := b.arrived
+		b.arrived--
+		b.mu.Unlock()
+		a.t.Fatal(fmt.Sprintf("timeout waiting at barrier; %d of %d participants arrived", arrived, b.n))
+	}
+}
+
+// arrive counts an arrival, with b.mu held, releasing the round if it's the
+// last one, and returns the channel closed when the round is released.
+func (b *Barrier) arrive() chan struct{} {
+	release := b.release
+	b.arrived++
+	if b.arrived == b.n {
+		close(b.release)
+		b.arrived = 0
+		b.release = make(chan struct{})
+	}
+	return release
+}
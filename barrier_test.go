@@ -0,0 +1,51 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBarrier(t *testing.T) {
+	b := NewBarrier(3)
+	for round := 0; round < 2; round++ {
+		assertPasses(t, func(t TestingT) {
+			g1 := Go(t, func(a *Asserter) { b.Arrive(a) })
+			g2 := Go(t, func(a *Asserter) { b.Arrive(a) })
+			b.Arrive(t)
+			g1.Wait()
+			g2.Wait()
+		})
+	}
+
+	waiting := Go(t, func(a *Asserter) { b.Arrive(a) })
+	assertFails(t, "2 of 3 participants arrived", func(t TestingT) {
+		AssertBlockedOn(t, OpSelect, (*Barrier).Arrive)
+		b.Arrive(New(t, short))
+	})
+
+	// The participant that timed out left, so the round takes two more.
+	assertPasses(t, func(t TestingT) {
+		g := Go(t, func(a *Asserter) { b.Arrive(a) })
+		b.Arrive(t)
+		g.Wait()
+		waiting.Wait()
+	})
+}
+
+func TestBarrierReleasedAtDeadline(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	b := NewBarrier(2)
+	g := Go(New(t, WithClock(clock)), func(a *Asserter) { b.Arrive(a) })
+	clock.BlockUntil(1)
+
+	// Hold the lock while the timeout fires, so that the participant only
+	// gets to check on the round after the last one has arrived.
+	b.mu.Lock()
+	clock.Advance(time.Duration(Default))
+	time.Sleep(10 * time.Millisecond)
+	b.arrive()
+	b.mu.Unlock()
+	assertPasses(t, func(t TestingT) {
+		g.Wait()
+	})
+}
@@ -0,0 +1,87 @@
+package chantest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// BenchRecv receives b.N values from ch, as fed by the code under test, and
+// reports the throughput in msgs/s.
+//
+// Receives don't time out, so that they aren't slowed down by timers; the
+// benchmark fails if ch is closed before b.N values are received.
+func BenchRecv[T any](b *testing.B, ch <-chan T) {
+	b.Helper()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		if _, ok := <-ch; !ok {
+			b.Fatalf("channel closed after %d of %d values", i, b.N)
+		}
+	}
+	reportThroughput(b, b.N, time.Since(start))
+}
+
+// BenchSend sends v to ch b.N times, to be consumed by the code under test,
+// and reports the throughput in msgs/s.
+//
+// Sends don't time out, so that they aren't slowed down by timers.
+func BenchSend[T any](b *testing.B, ch chan<- T, v T) {
+	b.Helper()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		ch <- v
+	}
+	reportThroughput(b, b.N, time.Since(start))
+}
+
+// MeasurePingPong sends v to in and receives a reply from out b.N times, and
+// reports the latency of each hand-off, half a round trip, in ns/handoff, and
+// the throughput in msgs/s, counting both the requests and the replies.
+func MeasurePingPong[In, Out any](b *testing.B, in chan<- In, v In, out <-chan Out) {
+	b.Helper()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		in <- v
+		if _, ok := <-out; !ok {
+			b.Fatalf("channel closed after %d of %d replies", i, b.N)
+		}
+	}
+	elapsed := time.Since(start)
+	b.ReportMetric(float64(elapsed.Nanoseconds())/float64(2*b.N), "ns/handoff")
+	reportThroughput(b, 2*b.N, elapsed)
+}
+
+func reportThroughput(b *testing.B, msgs int, elapsed time.Duration) {
+	if elapsed > 0 {
+		b.ReportMetric(float64(msgs)/elapsed.Seconds(), "msgs/s")
+	}
+}
+
+// allocsRuns is how many times AssertAllocsPerMsg runs its operation.
+const allocsRuns = 100
+
+// AssertAllocsPerMsg asserts that calling produce, which makes the code under
+// test send a value to ch, allocates at most max times on average, as measured
+// by testing.AllocsPerRun. Each run receives the produced value, failing if it
+// doesn't arrive quickly.
+//
+// Receiving doesn't allocate unless it times out, so only produce's
+// allocations are counted. Note that testing.AllocsPerRun sets GOMAXPROCS to 1
+// while it measures.
+func AssertAllocsPerMsg[T any](t TestingT, ch <-chan T, max float64, produce func(), msgAndArgs ...interface{}) {
+	a := New(t)
+	a.t.Helper()
+	allocs := testing.AllocsPerRun(allocsRuns, func() {
+		produce()
+		if _, ok := recv(a, ch); !ok {
+			a.t.Fatal("timeout waiting for channel send or receive")
+		}
+	})
+	if allocs > max {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("%v allocations per message, want at most %v", allocs, max), msgAndArgs...))
+	}
+}
@@ -0,0 +1,77 @@
+package chantest
+
+import "testing"
+
+func TestBenchHelpers(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		bench   func(b *testing.B)
+		metrics []string
+	}{{
+		"BenchRecv",
+		func(b *testing.B) {
+			ch, n := make(chan int), b.N
+			go func() {
+				for i := 0; i < n; i++ {
+					ch <- i
+				}
+			}()
+			BenchRecv(b, ch)
+		},
+		[]string{"msgs/s"},
+	}, {
+		"BenchSend",
+		func(b *testing.B) {
+			ch, n := make(chan int), b.N
+			go func() {
+				for i := 0; i < n; i++ {
+					<-ch
+				}
+			}()
+			BenchSend(b, ch, 1)
+		},
+		[]string{"msgs/s"},
+	}, {
+		"MeasurePingPong",
+		func(b *testing.B) {
+			in, out := make(chan int), make(chan string)
+			go func() {
+				for range in {
+					out <- "pong"
+				}
+			}()
+			defer close(in)
+			MeasurePingPong(b, in, 1, out)
+		},
+		[]string{"ns/handoff", "msgs/s"},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			r := testing.Benchmark(c.bench)
+			if r.N == 0 {
+				t.Fatal("benchmark didn't run")
+			}
+			for _, m := range c.metrics {
+				if r.Extra[m] <= 0 {
+					t.Errorf("missing %s metric: %v", m, r.Extra)
+				}
+			}
+		})
+	}
+}
+
+func TestAssertAllocsPerMsg(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	ch := make(chan []byte, 1)
+	assertPasses(t, func(t TestingT) {
+		AssertAllocsPerMsg(t, ch, 0, func() { ch <- nil })
+	})
+	var sink []byte
+	assertFails(t, "1 allocations per message, want at most 0", func(t TestingT) {
+		AssertAllocsPerMsg(t, ch, 0, func() {
+			sink = make([]byte, 64)
+			ch <- sink
+		})
+	})
+}
@@ -0,0 +1,159 @@
+package chantest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// An Op is a blocking channel operation, as reported in goroutine stack traces.
+type Op string
+
+// The blocking channel operations a goroutine can be parked on.
+const (
+	OpRecv   Op = "chan receive"
+	OpSend   Op = "chan send"
+	OpSelect Op = "select"
+)
+
+// pollInterval is how often assertions that can't be notified of changes, such
+// as AssertBlockedOn inspecting goroutine stacks, check again.
+const pollInterval = time.Millisecond
+
+// AssertBlockedOn calls Asserter.AssertBlockedOn on New(t).
+func AssertBlockedOn(t TestingT, op Op, fn interface{}, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	a.AssertBlockedOn(op, fn, msgAndArgs...)
+}
+
+// AssertBlockedOn asserts that some goroutine quickly gets parked on a channel
+// operation op directly inside the function fn.
+//
+// Stack traces don't identify channels, so the channel is instead identified
+// by the function that operates on it. fn must be either a function value or
+// a fully qualified function name as it appears in stack traces, e.g.
+// "example.com/pkg.(*Server).loop".
+//
+// Useful for checking that a goroutine is blocked, and blocked on the right
+// thing. The failure lists the goroutines that are blocked on channel
+// operations elsewhere.
+func (d Before) AssertBlockedOn(t TestingT, op Op, fn interface{}, msgAndArgs ...interface{}) {
+	a := d.on(t)
+	a.t.Helper()
+	name := funcName(fn)
+	var blocked []blockedGoroutine
+	if a.poll(func() bool {
+		blocked = blockedGoroutines()
+		for _, g := range blocked {
+			if g.op == op && g.fn == name {
+				return true
+			}
+		}
+		return false
+	}) {
+		return
+	}
+
+	var others []string
+	for _, g := range blocked {
+		others = append(others, fmt.Sprintf("%s in %s", g.op, g.fn))
+	}
+	a.t.Fatal(defaultOrCustomMessage(
+		fmt.Sprintf("timeout waiting for a goroutine blocked on %s in %s; blocked goroutines: %v", op, name, others),
+		msgAndArgs...,
+	))
+}
+
+// AssertNotReceiving calls Asserter.AssertNotReceiving on New(t).
+func AssertNotReceiving(t TestingT, fn interface{}, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	a.AssertNotReceiving(fn, msgAndArgs...)
+}
+
+// AssertNotReceiving asserts that, for a very short period of time, no
+// goroutine is parked on a receive, or a select, directly inside the function
+// fn, identified like in AssertBlockedOn.
+//
+// It's a probing alternative to AssertNoSend, which, if it fails, has already
+// delivered its value to the receiver it found, changing the state of the code
+// under test. AssertNotReceiving doesn't touch the channel; instead, it checks
+// goroutine stacks. So it can't tell which channel a goroutine is receiving
+// from, nor whether a select it's parked on receives at all, and misses
+// receivers that only get to the receive after the period.
+func (d Before) AssertNotReceiving(t TestingT, fn interface{}, msgAndArgs ...interface{}) {
+	a := d.on(t)
+	a.t.Helper()
+	name := funcName(fn)
+	var found blockedGoroutine
+	if a.poll(func() bool {
+		for _, g := range blockedGoroutines() {
+			if g.fn == name && (g.op == OpRecv || g.op == OpSelect) {
+				found = g
+				return true
+			}
+		}
+		return false
+	}) {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("goroutine blocked on %s in %s", found.op, name), msgAndArgs...))
+	}
+}
+
+func funcName(fn interface{}) string {
+	if name, ok := fn.(string); ok {
+		return name
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	// Method values are wrapped in a function with this suffix.
+	return strings.TrimSuffix(name, "-fm")
+}
+
+type blockedGoroutine struct {
+	op Op
+	fn string
+}
+
+// blockedGoroutines parses the stack traces of all goroutines and returns those
+// parked on a channel operation, with the innermost function they're in.
+func blockedGoroutines() []blockedGoroutine {
+	var blocked []blockedGoroutine
+	for _, trace := range bytes.Split(allStacks(), []byte("\n\n")) {
+		lines := strings.SplitN(string(trace), "\n", 3)
+		if len(lines) < 2 {
+			continue
+		}
+
+		// goroutine 7 [chan receive, 2 minutes]:
+		header := lines[0]
+		start, end := strings.Index(header, "["), strings.LastIndex(header, "]")
+		if start < 0 || end < start {
+			continue
+		}
+		state := header[start+1 : end]
+		if i := strings.Index(state, ","); i >= 0 {
+			state = state[:i]
+		}
+		var op Op
+		for _, o := range []Op{OpRecv, OpSend, OpSelect} {
+			// Also matches states like "chan receive (nil chan)".
+			if strings.HasPrefix(state, string(o)) {
+				op = o
+			}
+		}
+		if op == "" {
+			continue
+		}
+
+		// example.com/pkg.(*Server).loop(0xc000010000, ...)
+		fn := lines[1]
+		if i := strings.LastIndex(fn, "("); i >= 0 {
+			fn = fn[:i]
+		}
+		blocked = append(blocked, blockedGoroutine{op: op, fn: fn})
+	}
+	return blocked
+}
@@ -0,0 +1,42 @@
+package chantest
+
+import "testing"
+
+type blockedServer struct{ ch chan int }
+
+func (s *blockedServer) loop() { <-s.ch }
+
+func blockedSender(ch chan int) { ch <- 1 }
+
+func TestAssertBlockedOn(t *testing.T) {
+	s := &blockedServer{ch: make(chan int)}
+	go s.loop()
+	go blockedSender(make(chan int))
+	defer close(s.ch)
+
+	assertPasses(t, func(t TestingT) {
+		AssertBlockedOn(t, OpRecv, s.loop)
+		AssertBlockedOn(t, OpRecv, "github.com/canastic/chantest.(*blockedServer).loop")
+		AssertBlockedOn(t, OpSend, blockedSender)
+	})
+	assertFails(t, "chan receive in github.com/canastic/chantest.(*blockedServer).loop", func(t TestingT) {
+		short.AssertBlockedOn(t, OpSend, s.loop)
+	})
+}
+
+func TestAssertNotReceiving(t *testing.T) {
+	s := &blockedServer{ch: make(chan int)}
+	assertPasses(t, func(t TestingT) {
+		short.AssertNotReceiving(t, s.loop)
+	})
+
+	go s.loop()
+	defer close(s.ch)
+	AssertBlockedOn(t, OpRecv, s.loop)
+	assertFails(t, "goroutine blocked on chan receive in github.com/canastic/chantest.(*blockedServer).loop", func(t TestingT) {
+		AssertNotReceiving(t, s.loop)
+	})
+	assertPasses(t, func(t TestingT) {
+		New(t, short).AssertNotReceiving(blockedSender)
+	})
+}
@@ -0,0 +1,51 @@
+package chantest
+
+// A ChanAsserter makes the typed assertions on a single channel, with the
+// configuration of the Asserter it's created from.
+type ChanAsserter[T any] struct {
+	a  *Asserter
+	ch chan T
+}
+
+// On returns a ChanAsserter for ch.
+func On[T any](t TestingT, ch chan T) *ChanAsserter[T] {
+	return &ChanAsserter[T]{a: asserterFor(t), ch: ch}
+}
+
+// AssertRecvChan asserts that a channel is quickly received from ch, and
+// returns a ChanAsserter for it, for channels carrying per-session or
+// per-request channels.
+func AssertRecvChan[T any](t TestingT, ch <-chan chan T, msgAndArgs ...interface{}) *ChanAsserter[T] {
+	a := asserterFor(t)
+	a.t.Helper()
+	return On(a, Recv(a, ch, msgAndArgs...))
+}
+
+// Chan returns the channel.
+func (c *ChanAsserter[T]) Chan() chan T {
+	return c.ch
+}
+
+// Recv is Recv on the channel.
+func (c *ChanAsserter[T]) Recv(msgAndArgs ...interface{}) T {
+	c.a.t.Helper()
+	return Recv(c.a, c.ch, msgAndArgs...)
+}
+
+// NoRecv is NoRecv on the channel.
+func (c *ChanAsserter[T]) NoRecv(msgAndArgs ...interface{}) T {
+	c.a.t.Helper()
+	return NoRecv(c.a, c.ch, msgAndArgs...)
+}
+
+// Send is Send on the channel.
+func (c *ChanAsserter[T]) Send(v T, msgAndArgs ...interface{}) {
+	c.a.t.Helper()
+	Send(c.a, c.ch, v, msgAndArgs...)
+}
+
+// NoSend is NoSend on the channel.
+func (c *ChanAsserter[T]) NoSend(v T, msgAndArgs ...interface{}) {
+	c.a.t.Helper()
+	NoSend(c.a, c.ch, v, msgAndArgs...)
+}
@@ -0,0 +1,33 @@
+package chantest
+
+import "testing"
+
+func TestAssertRecvChan(t *testing.T) {
+	sessions := make(chan chan string, 1)
+	go func() {
+		session := make(chan string)
+		sessions <- session
+		session <- "hello"
+		if got := <-session; got != "bye" {
+			panic(got)
+		}
+	}()
+
+	session := AssertRecvChan(t, sessions)
+	if got := session.Recv(); got != "hello" {
+		t.Fatalf("got %q", got)
+	}
+	session.Send("bye")
+	idle := On(New(t, short), session.Chan())
+	idle.NoRecv()
+	idle.NoSend("again")
+
+	assertFails(t, "no session", func(t TestingT) {
+		AssertRecvChan(New(t, Before(0)), sessions, "no session")
+	})
+	assertFails(t, "unexpected channel receive", func(t TestingT) {
+		ch := make(chan int, 1)
+		ch <- 1
+		On(t, ch).NoRecv()
+	})
+}
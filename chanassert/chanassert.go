@@ -0,0 +1,112 @@
+// Package chanassert provides chantest assertions with testify's signatures:
+// they take a TestingT with an Errorf method, report failures with it, and
+// return whether they passed, instead of stopping the test.
+//
+// Like chantest's package-level functions, they wait for chantest.Default.
+package chanassert
+
+import (
+	"fmt"
+
+	"github.com/canastic/chantest"
+)
+
+// TestingT is the interface of testify's assert.TestingT.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+type tHelper interface {
+	Helper()
+}
+
+// Receives asserts that something is quickly received from ch, which must be a
+// channel.
+func Receives(t TestingT, ch interface{}, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return check(t, func(t chantest.TestingT) { chantest.AssertRecv(t, ch, msgAndArgs...) })
+}
+
+// NotReceives asserts that nothing is received from ch, which must be a
+// channel, for a very short period of time.
+func NotReceives(t TestingT, ch interface{}, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return check(t, func(t chantest.TestingT) { chantest.AssertNoRecv(t, ch, msgAndArgs...) })
+}
+
+// Sends asserts that v is quickly sent to ch, which must be a channel.
+func Sends(t TestingT, ch, v interface{}, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return check(t, func(t chantest.TestingT) { chantest.AssertSend(t, ch, v, msgAndArgs...) })
+}
+
+// NotSends asserts that v is not sent to ch, which must be a channel, for a
+// very short period of time.
+func NotSends(t TestingT, ch, v interface{}, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return check(t, func(t chantest.TestingT) { chantest.AssertNoSend(t, ch, v, msgAndArgs...) })
+}
+
+// ReceivesEqual asserts that a value equal to want is quickly received from
+// ch, as per chantest.AssertRecvEqual.
+func ReceivesEqual[T any](t TestingT, ch <-chan T, want T, opts ...chantest.EqualOption) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return check(t, func(t chantest.TestingT) { chantest.AssertRecvEqual(t, ch, want, opts...) })
+}
+
+// Returns asserts that do returns very quickly, as per chantest.Expect.
+func Returns(t TestingT, do func()) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return check(t, func(t chantest.TestingT) { chantest.Expect(t, do) })
+}
+
+// Blocks asserts that do doesn't return very quickly, as per
+// chantest.ExpectBlocked.
+func Blocks(t TestingT, do func()) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return check(t, func(t chantest.TestingT) { chantest.ExpectBlocked(t, do) })
+}
+
+// check runs assert with chantest.Go, so that its Fatal stops it without
+// stopping the test, and reports its failure, if any, to t.
+func check(t TestingT, assert func(t chantest.TestingT)) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	r := &reporter{t: t}
+	chantest.Go(r, func(a *chantest.Asserter) { assert(a) }).Wait()
+	return !r.failed
+}
+
+// reporter is a chantest.TestingT that reports Fatal calls to a TestingT as
+// Errorf calls.
+type reporter struct {
+	t      TestingT
+	failed bool
+}
+
+func (r *reporter) Helper() {
+	if h, ok := r.t.(tHelper); ok {
+		h.Helper()
+	}
+}
+
+func (r *reporter) Fatal(args ...interface{}) {
+	r.Helper()
+	r.failed = true
+	r.t.Errorf("%s", fmt.Sprint(args...))
+}
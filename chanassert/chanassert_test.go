@@ -0,0 +1,40 @@
+package chanassert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// errorfT is a TestingT recording the reported failures.
+type errorfT struct {
+	errors []string
+}
+
+func (t *errorfT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	ch := make(chan int, 1)
+	et := &errorfT{}
+
+	if !Sends(et, ch, 1) || !Receives(et, ch) || !NotReceives(et, ch) {
+		t.Fatalf("unexpected failures: %q", et.errors)
+	}
+	ch <- 2
+	if !ReceivesEqual(et, ch, 2) || !Returns(et, func() {}) || !Blocks(et, func() { select {} }) {
+		t.Fatalf("unexpected failures: %q", et.errors)
+	}
+
+	if Receives(et, ch, "nothing on %s", "ch") {
+		t.Fatal("Receives passed on empty channel")
+	}
+	ch <- 3
+	if NotSends(et, make(chan int, 1), 1) || ReceivesEqual(et, ch, 4) {
+		t.Fatal("assertions passed")
+	}
+	if len(et.errors) != 3 || et.errors[0] != "nothing on ch" || et.errors[1] != "unexpected channel receive" || !strings.Contains(et.errors[2], "got 3, want 4") {
+		t.Fatalf("unexpected failures: %q", et.errors)
+	}
+}
@@ -1,7 +1,13 @@
 // Package chantest implements utilities for testing concurrency.
+//
+// Assertions wait for some short amount of time, a Before duration, for
+// something to happen or not. Functions and methods that take a TestingT wait
+// for Default, unless they're given an Asserter, whose configuration they use
+// instead. Before methods always wait for their receiver.
 package chantest
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"time"
@@ -11,34 +17,81 @@ import (
 // if not blocked or doing something slow.
 const Default = Before(100 * time.Millisecond)
 
-// Expect calls Before.Expect on Default.
+// Expect calls Asserter.Expect on New(t).
 func Expect(t TestingT, do func()) {
-	t.Helper()
-	Default.Expect(t, do)
+	a := asserterFor(t)
+	a.t.Helper()
+	a.Expect(do)
+}
+
+// ExpectOrdered calls Asserter.ExpectOrdered on New(t).
+func ExpectOrdered(t TestingT, do ...func()) {
+	a := asserterFor(t)
+	a.t.Helper()
+	a.ExpectOrdered(do...)
 }
 
-// AssertRecv calls Before.AssertRecv on Default.
+// ExpectBlocked calls Asserter.ExpectBlocked on New(t).
+func ExpectBlocked(t TestingT, do func()) {
+	a := asserterFor(t)
+	a.t.Helper()
+	a.ExpectBlocked(do)
+}
+
+// AssertUnblockedBy calls Asserter.AssertUnblockedBy on New(t).
+func AssertUnblockedBy(t TestingT, do, release func()) {
+	a := asserterFor(t)
+	a.t.Helper()
+	a.AssertUnblockedBy(do, release)
+}
+
+// AssertRecv calls Asserter.AssertRecv on New(t).
 func AssertRecv(t TestingT, ch interface{}, msgAndArgs ...interface{}) interface{} {
-	t.Helper()
-	return Default.AssertRecv(t, ch, msgAndArgs...)
+	a := asserterFor(t)
+	a.t.Helper()
+	return a.AssertRecv(ch, msgAndArgs...)
 }
 
-// AssertNoRecv calls Before.AssertNoRecv on Default.
+// AssertNoRecv calls Asserter.AssertNoRecv on New(t).
 func AssertNoRecv(t TestingT, ch interface{}, msgAndArgs ...interface{}) interface{} {
-	t.Helper()
-	return Default.AssertNoRecv(t, ch, msgAndArgs...)
+	a := asserterFor(t)
+	a.t.Helper()
+	return a.AssertNoRecv(ch, msgAndArgs...)
+}
+
+// AssertRecvAny calls Asserter.AssertRecvAny on New(t).
+func AssertRecvAny(t TestingT, chs ...interface{}) (index int, v interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	return a.AssertRecvAny(chs...)
+}
+
+// AssertRecvEach calls Asserter.AssertRecvEach on New(t).
+func AssertRecvEach(t TestingT, chs ...interface{}) []interface{} {
+	a := asserterFor(t)
+	a.t.Helper()
+	return a.AssertRecvEach(chs...)
 }
 
-// AssertSend calls Before.AssertSend on Default.
+// AssertRecvInOrder calls Asserter.AssertRecvInOrder on New(t).
+func AssertRecvInOrder(t TestingT, chs ...interface{}) []interface{} {
+	a := asserterFor(t)
+	a.t.Helper()
+	return a.AssertRecvInOrder(chs...)
+}
+
+// AssertSend calls Asserter.AssertSend on New(t).
 func AssertSend(t TestingT, ch, v interface{}, msgAndArgs ...interface{}) {
-	t.Helper()
-	Default.AssertSend(t, ch, v, msgAndArgs...)
+	a := asserterFor(t)
+	a.t.Helper()
+	a.AssertSend(ch, v, msgAndArgs...)
 }
 
-// AssertNoSend calls Before.AssertNoSend on Default.
+// AssertNoSend calls Asserter.AssertNoSend on New(t).
 func AssertNoSend(t TestingT, ch, v interface{}, msgAndArgs ...interface{}) {
-	t.Helper()
-	Default.AssertNoSend(t, ch, v, msgAndArgs...)
+	a := asserterFor(t)
+	a.t.Helper()
+	a.AssertNoSend(ch, v, msgAndArgs...)
 }
 
 // Before is the amount of time to wait before failing an expectation.
@@ -51,26 +104,149 @@ type Before time.Duration
 // point that somehow reads or sends to the do function, and to synchronize
 // its continuation with
 func (d Before) Expect(t TestingT, do func()) {
-	t.Helper()
+	a := d.on(t)
+	a.t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		do()
+	}()
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case <-done:
+	case <-timeout:
+		a.t.Fatal("timeout waiting for channel send or receive")
+	}
+}
+
+// ErrTimeout is the error TryExpect returns when do doesn't return in time.
+var ErrTimeout = errors.New("timeout waiting for channel send or receive")
+
+// TryExpect is like Expect, but returns ErrTimeout instead of failing a test,
+// so that it can be composed with retries and fallbacks, or used outside of
+// tests. It waits for d as measured by the system clock.
+func TryExpect(d Before, do func()) error {
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
 		do()
 	}()
+	timeout, stop := startTimer(systemClock{}, time.Duration(d))
+	defer stop()
 	select {
 	case <-done:
-	case <-time.After(time.Duration(d)):
-		t.Fatal("timeout waiting for channel send or receive")
+		return nil
+	case <-timeout:
+		return ErrTimeout
+	}
+}
+
+// ExpectOrdered runs each do function concurrently and fails the test if they
+// don't all return very quickly, or if they return in an order other than the
+// one they're given in. The failure reports the observed order.
+//
+// Useful for testing that waiters are released in FIFO order, or that a
+// shutdown sequence happens in the expected steps.
+func (d Before) ExpectOrdered(t TestingT, do ...func()) {
+	a := d.on(t)
+	a.t.Helper()
+	done := make(chan int, len(do))
+	for i, do := range do {
+		i, do := i, do
+		go func() {
+			defer func() { done <- i }()
+			do()
+		}()
+	}
+
+	timeout, stop := a.timeout()
+	defer stop()
+	order := make([]int, 0, len(do))
+	for len(order) < len(do) {
+		select {
+		case i := <-done:
+			order = append(order, i)
+		case <-timeout:
+			a.t.Fatal(fmt.Sprintf("timeout waiting for %d funcs to complete; completed in order %v", len(do), order))
+			return
+		}
+	}
+
+	for i, got := range order {
+		if got != i {
+			a.t.Fatal(fmt.Sprintf("funcs completed in order %v; expected them in the given order", order))
+			return
+		}
+	}
+}
+
+// ExpectBlocked is the inverse of Expect: it fails the test if do returns
+// before the timeout, as it's expected to stay blocked.
+//
+// Useful for testing that a goroutine is parked waiting for some input. do
+// keeps running in the background after ExpectBlocked returns.
+func (d Before) ExpectBlocked(t TestingT, do func()) {
+	a := d.on(t)
+	a.t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		do()
+	}()
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case <-done:
+		a.t.Fatal("unexpected return from function expected to block")
+	case <-timeout:
+	}
+}
+
+// AssertUnblockedBy asserts that do stays blocked until release is called, and
+// that it returns very quickly after that.
+//
+// It combines ExpectBlocked and Expect for the common case of a waiter that
+// should be parked until some signal.
+func (d Before) AssertUnblockedBy(t TestingT, do, release func()) {
+	a := d.on(t)
+	a.t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		do()
+	}()
+	blocked, stop := a.timeout()
+	defer stop()
+	select {
+	case <-done:
+		a.t.Fatal("unexpected return from function expected to block until released")
+		return
+	case <-blocked:
+	}
+
+	release()
+
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case <-done:
+	case <-timeout:
+		a.t.Fatal("timeout waiting for function to return after release")
 	}
 }
 
 // AssertRecv asserts that something is quickly received from ch, which must be a channel.
 // custom msgAndArgs cand be added, with first argument being the formatted string
 func (d Before) AssertRecv(t TestingT, ch interface{}, msgAndArgs ...interface{}) interface{} {
-	t.Helper()
-	v, ok := d.assertRecv(t, ch)
+	a := d.on(t)
+	a.t.Helper()
+	v, ok := a.assertRecv(ch)
 	if !ok {
-		t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...))
+		var attempts string
+		if v, ok, attempts = retry(a, func(a *Asserter) (interface{}, bool) { return a.assertRecv(ch) }); !ok {
+			a.t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...) + attempts)
+		}
 	}
 	return v
 }
@@ -78,35 +254,139 @@ func (d Before) AssertRecv(t TestingT, ch interface{}, msgAndArgs ...interface{}
 // AssertNoRecv asserts that nothing is received from ch, which must be a channel, for a very short period of time.
 // custom msgAndArgs cand be added, with first argument being the formatted string
 func (d Before) AssertNoRecv(t TestingT, ch interface{}, msgAndArgs ...interface{}) interface{} {
-	t.Helper()
-	v, ok := d.assertRecv(t, ch)
+	a := d.on(t)
+	a.t.Helper()
+	v, ok := a.assertRecv(ch)
 	if !ok {
 		return nil
 	}
-	t.Fatal(defaultOrCustomMessage("unexpected channel receive", msgAndArgs...))
+	a.t.Fatal(defaultOrCustomMessage("unexpected channel receive", msgAndArgs...))
 	return v
 }
 
+// AssertRecvAny asserts that something is quickly received from any of chs,
+// which must be channels, and returns the index of the channel and the value.
+func (d Before) AssertRecvAny(t TestingT, chs ...interface{}) (index int, v interface{}) {
+	a := d.on(t)
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	cases := make([]reflect.SelectCase, len(chs)+1)
+	for i, ch := range chs {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)}
+	}
+	cases[len(chs)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)}
+	chosen, recv, recvOK := reflect.Select(cases)
+	if chosen == len(chs) {
+		a.t.Fatal(fmt.Sprintf("timeout waiting for a receive from any of %d channels", len(chs)))
+		return -1, nil
+	}
+	if recvOK {
+		a.matched(chs[chosen], recv.Interface())
+	}
+	return chosen, recv.Interface()
+}
+
+// AssertRecvEach asserts that something is quickly received from each of chs,
+// which must be channels, and returns the values, in the order of chs. The
+// channels share a single timeout, and the failure lists those that were
+// silent.
+func (d Before) AssertRecvEach(t TestingT, chs ...interface{}) []interface{} {
+	a := d.on(t)
+	a.t.Helper()
+	values, order, ok := a.recvEach(chs)
+	if !ok {
+		received := make([]bool, len(chs))
+		for _, i := range order {
+			received[i] = true
+		}
+		var silent []int
+		for i, ok := range received {
+			if !ok {
+				silent = append(silent, i)
+			}
+		}
+		a.t.Fatal(fmt.Sprintf("timeout waiting for receives; channels %v were silent", silent))
+	}
+	return values
+}
+
+// AssertRecvInOrder is like AssertRecvEach, but also fails if the values
+// don't arrive in the order of chs. The failure reports the observed order,
+// as indices of chs.
+func (d Before) AssertRecvInOrder(t TestingT, chs ...interface{}) []interface{} {
+	a := d.on(t)
+	a.t.Helper()
+	values, order, ok := a.recvEach(chs)
+	if !ok {
+		a.t.Fatal(fmt.Sprintf("timeout waiting for receives from %d channels; received in order %v", len(chs), order))
+		return values
+	}
+	for i, got := range order {
+		if got != i {
+			a.t.Fatal(fmt.Sprintf("channels received from in order %v; expected them in the given order", order))
+			break
+		}
+	}
+	return values
+}
+
+// recvEach receives a value from each of chs, and returns the values, in the
+// order of chs, and the order in which they arrived, as indices of chs. ok
+// is false if not all arrived before the timeout.
+func (a *Asserter) recvEach(chs []interface{}) (values []interface{}, order []int, ok bool) {
+	timeout, stop := a.timeout()
+	defer stop()
+	values = make([]interface{}, len(chs))
+	cases := make([]reflect.SelectCase, len(chs)+1)
+	for i, ch := range chs {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)}
+	}
+	cases[len(chs)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)}
+	for len(order) < len(chs) {
+		chosen, recv, recvOK := reflect.Select(cases)
+		if chosen == len(chs) {
+			return values, order, false
+		}
+		if recvOK {
+			a.matched(chs[chosen], recv.Interface())
+		}
+		values[chosen] = recv.Interface()
+		order = append(order, chosen)
+		// reflect.Select ignores cases with a zero Chan.
+		cases[chosen].Chan = reflect.Value{}
+	}
+	return values, order, true
+}
+
 // AssertSend asserts that v is quickly sent from ch, which must be a channel.
 // custom msgAndArgs cand be added, with first argument being the formatted string
 func (d Before) AssertSend(t TestingT, ch, v interface{}, msgAndArgs ...interface{}) {
-	t.Helper()
-	if !d.assertSend(t, ch, v) {
-		t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...))
+	a := d.on(t)
+	a.t.Helper()
+	if a.assertSend(ch, v) {
+		return
+	}
+	if _, ok, attempts := retry(a, func(a *Asserter) (struct{}, bool) { return struct{}{}, a.assertSend(ch, v) }); !ok {
+		a.t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...) + attempts)
 	}
 }
 
 // AssertNoSend asserts that v is not sent to ch, which must be a channel, for a very short period of time.
 // custom msgAndArgs cand be added, with first argument being the formatted string
+//
+// If it fails, v has been delivered; AssertNotReceiving probes for receivers
+// without sending anything.
 func (d Before) AssertNoSend(t TestingT, ch, v interface{}, msgAndArgs ...interface{}) {
-	t.Helper()
-	if d.assertSend(t, ch, v) {
-		t.Fatal(defaultOrCustomMessage("unexpected channel receive", msgAndArgs...))
+	a := d.on(t)
+	a.t.Helper()
+	if a.assertSend(ch, v) {
+		a.t.Fatal(defaultOrCustomMessage("unexpected channel receive", msgAndArgs...))
 	}
 }
 
-func (d Before) assertRecv(t TestingT, ch interface{}) (interface{}, bool) {
-	t.Helper()
+func (a *Asserter) assertRecv(ch interface{}) (interface{}, bool) {
+	a.t.Helper()
 
 	// lol no generics
 	//
@@ -115,25 +395,30 @@ func (d Before) assertRecv(t TestingT, ch interface{}) (interface{}, bool) {
 	// select {
 	// case v = <-ch:
 	//    chosen = 0
-	// case <-time.After(time.Duration(d)):
+	// case <-timeout:
 	//    chosen = 1
 	// }
-	chosen, recv, _ := reflect.Select([]reflect.SelectCase{{
+	timeout, stop := a.timeout()
+	defer stop()
+	chosen, recv, recvOK := reflect.Select([]reflect.SelectCase{{
 		Dir:  reflect.SelectRecv,
 		Chan: reflect.ValueOf(ch),
 	}, {
 		Dir:  reflect.SelectRecv,
-		Chan: reflect.ValueOf(time.After(time.Duration(d))),
+		Chan: reflect.ValueOf(timeout),
 	}})
 	if chosen != 0 {
 		return nil, false
 	}
 
+	if recvOK {
+		a.matched(ch, recv.Interface())
+	}
 	return recv.Interface(), true
 }
 
-func (d Before) assertSend(t TestingT, ch, v interface{}) bool {
-	t.Helper()
+func (a *Asserter) assertSend(ch, v interface{}) bool {
+	a.t.Helper()
 
 	// lol no generics
 	//
@@ -142,16 +427,18 @@ func (d Before) assertSend(t TestingT, ch, v interface{}) bool {
 	// select {
 	// case ch <- v:
 	//    chosen = 0
-	// case <-time.After(time.Duration(d)):
+	// case <-timeout:
 	//    chosen = 1
 	// }
+	timeout, stop := a.timeout()
+	defer stop()
 	chosen, _, _ := reflect.Select([]reflect.SelectCase{{
 		Chan: reflect.ValueOf(ch),
 		Dir:  reflect.SelectSend,
 		Send: reflect.ValueOf(v),
 	}, {
 		Dir:  reflect.SelectRecv,
-		Chan: reflect.ValueOf(time.After(time.Duration(d))),
+		Chan: reflect.ValueOf(timeout),
 	}})
 
 	return chosen == 0
@@ -159,10 +446,17 @@ func (d Before) assertSend(t TestingT, ch, v interface{}) bool {
 
 // defaultOrCustomMessage tries to format customMsgAndArgs as format string and optional args,
 // if the formatting returns a non-empty string, it returns it, otherwise returns defaultMessage
+//
+// Fields in customMsgAndArgs are left out of the formatting, and appended to
+// the message instead.
 func defaultOrCustomMessage(defaultMessage string, customMsgAndArgs ...interface{}) string {
-	msg := messageFromMsgAndArgs(customMsgAndArgs...)
+	args, fields := splitFields(customMsgAndArgs)
+	msg := messageFromMsgAndArgs(args...)
 	if msg == "" {
-		return defaultMessage
+		msg = defaultMessage
+	}
+	if len(fields) > 0 {
+		msg += " " + fields.String()
 	}
 	return msg
 }
@@ -1,7 +1,195 @@
 package chantest
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestCanUseT(t *testing.T) {
 	var _ TestingT = t
 }
+
+// fakeT is a TestingT that records the failure and stops the calling
+// goroutine, like *testing.T does.
+type fakeT struct {
+	failed bool
+	msg    string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Fatal(args ...interface{}) {
+	t.failed = true
+	t.msg = fmt.Sprint(args...)
+	runtime.Goexit()
+}
+
+// failure runs f in a goroutine against a fakeT and returns the failure
+// message, if any.
+func failure(f func(t TestingT)) (string, bool) {
+	ft := &fakeT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(ft)
+	}()
+	<-done
+	return ft.msg, ft.failed
+}
+
+func assertFails(t *testing.T, want string, f func(t TestingT)) {
+	t.Helper()
+	msg, failed := failure(f)
+	if !failed {
+		t.Fatalf("expected failure containing %q", want)
+	}
+	if !strings.Contains(msg, want) {
+		t.Fatalf("expected failure containing %q, got %q", want, msg)
+	}
+}
+
+func assertPasses(t *testing.T, f func(t TestingT)) {
+	t.Helper()
+	if msg, failed := failure(f); failed {
+		t.Fatalf("unexpected failure: %s", msg)
+	}
+}
+
+const short = Before(10 * time.Millisecond)
+
+// rebind returns an Asserter on t with a's configuration, including its
+// registered channels, which New(t, ...) wouldn't keep.
+func rebind(t TestingT, a *Asserter) *Asserter {
+	return &Asserter{t: t, config: a.config}
+}
+
+func TestTryExpect(t *testing.T) {
+	if err := TryExpect(short, func() {}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	block := make(chan struct{})
+	defer close(block)
+	if err := TryExpect(short, func() { <-block }); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("got %v, want ErrTimeout", err)
+	}
+}
+
+func TestExpectOrdered(t *testing.T) {
+	// releasedInOrder returns funcs that block until released in the given
+	// order.
+	releasedInOrder := func(order ...int) []func() {
+		steps := make([]chan struct{}, len(order))
+		do := make([]func(), len(order))
+		for i := range steps {
+			step := make(chan struct{})
+			steps[i] = step
+			do[i] = func() { <-step }
+		}
+		go func() {
+			for _, i := range order {
+				close(steps[i])
+				time.Sleep(time.Millisecond)
+			}
+		}()
+		return do
+	}
+
+	assertPasses(t, func(t TestingT) {
+		ExpectOrdered(t, releasedInOrder(0, 1, 2)...)
+	})
+	assertFails(t, "order [1 0 2]", func(t TestingT) {
+		ExpectOrdered(t, releasedInOrder(1, 0, 2)...)
+	})
+	assertFails(t, "completed in order [0]", func(t TestingT) {
+		short.ExpectOrdered(t, func() {}, func() { select {} })
+	})
+}
+
+func TestExpectBlocked(t *testing.T) {
+	ch := make(chan struct{})
+	assertPasses(t, func(t TestingT) {
+		short.ExpectBlocked(t, func() { <-ch })
+	})
+	close(ch)
+	assertFails(t, "unexpected return", func(t TestingT) {
+		short.ExpectBlocked(t, func() { <-ch })
+	})
+}
+
+func TestAssertUnblockedBy(t *testing.T) {
+	ch := make(chan struct{})
+	assertPasses(t, func(t TestingT) {
+		short.AssertUnblockedBy(t, func() { <-ch }, func() { close(ch) })
+	})
+	assertFails(t, "expected to block", func(t TestingT) {
+		short.AssertUnblockedBy(t, func() { <-ch }, func() {})
+	})
+	assertFails(t, "after release", func(t TestingT) {
+		short.AssertUnblockedBy(t, func() { select {} }, func() {})
+	})
+}
+
+func TestAssertRecvInOrder(t *testing.T) {
+	// Each send only happens once the previous one is received.
+	a, b, c := make(chan int), make(chan int), make(chan int)
+	inOrder := func(first, second, third chan int) {
+		go func() {
+			first <- 1
+			second <- 2
+			third <- 3
+		}()
+	}
+
+	inOrder(a, b, c)
+	assertPasses(t, func(t TestingT) {
+		values := AssertRecvInOrder(t, a, b, c)
+		if values[0] != 1 || values[2] != 3 {
+			t.Fatal("unexpected values", values)
+		}
+	})
+	inOrder(b, a, c)
+	assertFails(t, "channels received from in order [1 0 2]; expected them in the given order", func(t TestingT) {
+		AssertRecvInOrder(t, a, b, c)
+	})
+	go func() { c <- 1 }()
+	assertFails(t, "timeout waiting for receives from 2 channels; received in order [1]", func(t TestingT) {
+		short.AssertRecvInOrder(t, a, c)
+	})
+}
+
+// afterClock is the system clock with a new timer for each assertion, and no
+// way to stop them early.
+type afterClock struct{}
+
+func (afterClock) Now() time.Time {
+	return time.Now()
+}
+
+func (afterClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func BenchmarkAssertRecv(b *testing.B) {
+	for _, c := range []struct {
+		name  string
+		clock Clock
+	}{
+		{"reused timers", systemClock{}},
+		{"time.After", afterClock{}},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			a := New(b, WithClock(c.clock))
+			ch := make(chan int, 1)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ch <- i
+				a.AssertRecv(ch)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Package chantestcmp compares received values with go-cmp in chantest's
+// assertions. It's a module of its own, so that chantest itself doesn't
+// depend on go-cmp.
+package chantestcmp
+
+import (
+	"github.com/canastic/chantest"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Options returns an EqualOption that compares values with cmp.Equal and
+// opts, such as cmp.Comparer, cmpopts.EquateApprox or cmpopts.IgnoreFields.
+// The failure shows cmp.Diff, with - for the expected value and + for the
+// received one.
+//
+// Pass it to chantest.AssertRecvEqual, or to chantest.WithEqual for every
+// assertion comparing received values.
+func Options(opts ...cmp.Option) chantest.EqualOption {
+	return chantest.CompareWith(func(got, want interface{}) string {
+		if cmp.Equal(want, got, opts...) {
+			return ""
+		}
+		return "-want +got:\n" + cmp.Diff(want, got, opts...)
+	})
+}
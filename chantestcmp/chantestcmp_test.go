@@ -0,0 +1,47 @@
+package chantestcmp
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/canastic/chantest"
+	"github.com/google/go-cmp/cmp"
+)
+
+type reading struct {
+	Value float64
+	unit  string
+}
+
+// failT is a chantest.TestingT recording the failure.
+type failT struct {
+	failure string
+}
+
+func (t *failT) Helper() {}
+
+func (t *failT) Fatal(args ...interface{}) {
+	t.failure += args[0].(string)
+}
+
+func TestOptions(t *testing.T) {
+	approx := Options(
+		cmp.AllowUnexported(reading{}),
+		cmp.Comparer(func(a, b float64) bool { return math.Abs(a-b) < 0.01 }),
+	)
+
+	ch := make(chan reading, 2)
+	ch <- reading{Value: 1.001, unit: "m"}
+	chantest.AssertRecvEqual(t, ch, reading{Value: 1, unit: "m"}, approx)
+
+	ch <- reading{Value: 1.001, unit: "m"}
+	ch <- reading{Value: 2, unit: "m"}
+	chantest.Consume(ch).Expect(reading{Value: 1, unit: "m"}).Run(chantest.New(t, chantest.WithEqual(approx)))
+
+	ft := &failT{}
+	chantest.AssertRecvEqual(ft, ch, reading{Value: 1, unit: "m"}, approx)
+	if !strings.Contains(ft.failure, "-want +got:") || !strings.Contains(ft.failure, "Value") {
+		t.Fatalf("unexpected failure: %s", ft.failure)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/canastic/chantest/chantestcmp
+
+go 1.18
+
+require (
+	github.com/canastic/chantest v0.0.0
+	github.com/google/go-cmp v0.6.0
+)
+
+replace github.com/canastic/chantest => ../
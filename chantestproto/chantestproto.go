@@ -0,0 +1,32 @@
+// Package chantestproto compares received protobuf messages with proto.Equal
+// in chantest's assertions, since reflect.DeepEqual looks at their internal
+// state. It's a module of its own, so that chantest itself doesn't depend on
+// protobuf.
+package chantestproto
+
+import (
+	"github.com/canastic/chantest"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// Equal returns an EqualOption that compares proto.Message values with
+// proto.Equal, and values containing them, like slices of messages, with
+// go-cmp and protocmp. The failure shows a field-level diff, with - for the
+// expected value and + for the received one.
+//
+// Pass it to chantest.AssertRecvEqual, or to chantest.WithEqual for every
+// assertion comparing received values.
+func Equal() chantest.EqualOption {
+	return chantest.CompareWith(func(got, want interface{}) string {
+		if g, ok := got.(proto.Message); ok {
+			if w, ok := want.(proto.Message); ok && proto.Equal(g, w) {
+				return ""
+			}
+		} else if cmp.Equal(want, got, protocmp.Transform()) {
+			return ""
+		}
+		return "-want +got:\n" + cmp.Diff(want, got, protocmp.Transform())
+	})
+}
@@ -0,0 +1,37 @@
+package chantestproto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/canastic/chantest"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// failT is a chantest.TestingT recording the failure.
+type failT struct {
+	failure string
+}
+
+func (t *failT) Helper() {}
+
+func (t *failT) Fatal(args ...interface{}) {
+	t.failure += args[0].(string)
+}
+
+func TestEqual(t *testing.T) {
+	ch := make(chan *structpb.Value, 2)
+	ch <- structpb.NewStringValue("created")
+	chantest.AssertRecvEqual(t, ch, structpb.NewStringValue("created"), Equal())
+
+	batches := make(chan []*structpb.Value, 1)
+	batches <- []*structpb.Value{structpb.NewNumberValue(1), structpb.NewBoolValue(true)}
+	chantest.AssertRecvEqual(t, batches, []*structpb.Value{structpb.NewNumberValue(1), structpb.NewBoolValue(true)}, Equal())
+
+	ch <- structpb.NewStringValue("deleted")
+	ft := &failT{}
+	chantest.AssertRecvEqual(ft, ch, structpb.NewStringValue("created"), Equal())
+	if !strings.Contains(ft.failure, "-want +got:") || !strings.Contains(ft.failure, "string_value") {
+		t.Fatalf("unexpected failure: %s", ft.failure)
+	}
+}
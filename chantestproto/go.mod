@@ -0,0 +1,11 @@
+module github.com/canastic/chantest/chantestproto
+
+go 1.18
+
+require (
+	github.com/canastic/chantest v0.0.0
+	github.com/google/go-cmp v0.6.0
+	google.golang.org/protobuf v1.33.0
+)
+
+replace github.com/canastic/chantest => ../
@@ -0,0 +1,191 @@
+// Package chantestvet provides an analyzer reporting misuse of chantest that
+// the compiler can't catch, because chantest's reflection-based assertions
+// take channels and values as interface{}.
+//
+// It can be run with go vet through cmd/chantestvet:
+//
+//	go vet -vettool=$(which chantestvet) ./...
+package chantestvet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const chantestPath = "github.com/canastic/chantest"
+
+// Analyzer reports calls to chantest's reflection-based assertions with
+// arguments that aren't channels, or values that can't be sent to them,
+// AssertNoSend on unbuffered channels, and Before durations of zero.
+var Analyzer = &analysis.Analyzer{
+	Name:     "chantest",
+	Doc:      "report misuse of chantest assertions",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// chanArgs are the reflection-based assertions taking a channel, and whether
+// they also take a value to send to it.
+var chanArgs = map[string]bool{
+	"AssertRecv":   false,
+	"AssertNoRecv": false,
+	"AssertSend":   true,
+	"AssertNoSend": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		if isBefore(pass.TypesInfo.TypeOf(call.Fun)) && len(call.Args) == 1 {
+			if tv, ok := pass.TypesInfo.Types[call.Args[0]]; ok && tv.Value != nil && constant.Sign(tv.Value) == 0 {
+				pass.Reportf(call.Pos(), "Before(0) makes assertions time out right away; use a positive duration")
+			}
+			return true
+		}
+
+		name, first, ok := assertion(pass, call)
+		if !ok {
+			return true
+		}
+		sends := chanArgs[name]
+		if len(call.Args) <= first || sends && len(call.Args) <= first+1 {
+			return true
+		}
+		chArg := call.Args[first]
+		ct, ok := pass.TypesInfo.TypeOf(chArg).Underlying().(*types.Chan)
+		if !ok {
+			if t := pass.TypesInfo.TypeOf(chArg); !types.IsInterface(t) {
+				pass.Reportf(chArg.Pos(), "%s called with %s, which isn't a channel", name, t)
+			}
+			return true
+		}
+		if !sends {
+			return true
+		}
+		v := call.Args[first+1]
+		if vt := pass.TypesInfo.TypeOf(v); vt != nil && !types.IsInterface(vt) && !types.AssignableTo(vt, ct.Elem()) {
+			pass.Reportf(v.Pos(), "%s of %s to a channel of %s", name, vt, ct.Elem())
+		}
+		if name == "AssertNoSend" && unbuffered(pass, chArg, stack) {
+			pass.Reportf(call.Pos(), "AssertNoSend on an unbuffered channel delivers the value to a receiver, if there's one, even though the assertion fails")
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// assertion returns the name of the chantest assertion call calls, if it's
+// one of chanArgs, and the index of its channel argument.
+func assertion(pass *analysis.Pass, call *ast.CallExpr) (name string, first int, ok bool) {
+	var id *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return "", 0, false
+	}
+	fn, _ := pass.TypesInfo.Uses[id].(*types.Func)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != chantestPath {
+		return "", 0, false
+	}
+	if _, ok := chanArgs[fn.Name()]; !ok {
+		return "", 0, false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv != nil && isAsserter(recv.Type()) {
+		// Asserter methods don't take a TestingT.
+		return fn.Name(), 0, true
+	}
+	return fn.Name(), 1, true
+}
+
+func isBefore(t types.Type) bool {
+	return isNamed(t, "Before")
+}
+
+func isAsserter(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	return isNamed(t, "Asserter")
+}
+
+func isNamed(t types.Type, name string) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Name() == name && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == chantestPath
+}
+
+// unbuffered reports whether expr is a local variable only ever assigned an
+// unbuffered channel made in the enclosing function, as found in stack.
+func unbuffered(pass *analysis.Pass, expr ast.Expr, stack []ast.Node) bool {
+	id, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok {
+		return false
+	}
+	var body *ast.BlockStmt
+	for i := len(stack) - 1; i >= 0 && body == nil; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+	}
+	if body == nil {
+		return false
+	}
+
+	assigned, made := 0, false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				lid, ok := lhs.(*ast.Ident)
+				if !ok || pass.TypesInfo.ObjectOf(lid) != obj {
+					continue
+				}
+				assigned++
+				if len(n.Rhs) == len(n.Lhs) {
+					made = isUnbufferedMake(pass, n.Rhs[i])
+				}
+			}
+		case *ast.UnaryExpr:
+			// Its address could be used to assign it elsewhere.
+			if uid, ok := n.X.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(uid) == obj {
+				assigned += 2
+			}
+		}
+		return true
+	})
+	return assigned == 1 && made
+}
+
+func isUnbufferedMake(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	if id, ok := call.Fun.(*ast.Ident); !ok || pass.TypesInfo.Uses[id] != types.Universe.Lookup("make") {
+		return false
+	}
+	if len(call.Args) == 1 {
+		return true
+	}
+	tv := pass.TypesInfo.Types[call.Args[1]]
+	return tv.Value != nil && constant.Sign(tv.Value) == 0
+}
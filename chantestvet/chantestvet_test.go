@@ -0,0 +1,15 @@
+package chantestvet
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestSelectTimeoutAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), SelectTimeoutAnalyzer, "b")
+}
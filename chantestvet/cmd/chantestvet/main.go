@@ -0,0 +1,14 @@
+// Command chantestvet runs the chantestvet analyzers, standalone or as a go
+// vet tool:
+//
+//	go vet -vettool=$(which chantestvet) ./...
+package main
+
+import (
+	"github.com/canastic/chantest/chantestvet"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(chantestvet.Analyzer, chantestvet.SelectTimeoutAnalyzer)
+}
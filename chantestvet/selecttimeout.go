@@ -0,0 +1,145 @@
+package chantestvet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// SelectTimeoutAnalyzer reports, in test files, hand-rolled timeouts of
+// channel operations that a chantest assertion can replace, like:
+//
+//	select {
+//	case v := <-ch:
+//	case <-time.After(time.Second):
+//		t.Fatal("timeout")
+//	}
+var SelectTimeoutAnalyzer = &analysis.Analyzer{
+	Name:     "chantestselect",
+	Doc:      "suggest chantest assertions for select statements with a time.After timeout in tests",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runSelectTimeout,
+}
+
+func runSelectTimeout(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.SelectStmt)(nil)}, func(n ast.Node) {
+		if !strings.HasSuffix(pass.Fset.File(n.Pos()).Name(), "_test.go") {
+			return
+		}
+		sel := n.(*ast.SelectStmt)
+		if len(sel.Body.List) != 2 {
+			return
+		}
+		op, timeout := sel.Body.List[0].(*ast.CommClause), sel.Body.List[1].(*ast.CommClause)
+		if isTimeAfter(pass, timeout.Comm) == nil {
+			op, timeout = timeout, op
+		}
+		d := isTimeAfter(pass, timeout.Comm)
+		if d == nil || op.Comm == nil {
+			return
+		}
+
+		before := "chantest."
+		if s := render(pass, d); s != "" {
+			before = "chantest.Before(" + s + ")."
+		}
+		fails := func(c *ast.CommClause) bool { return failsTest(pass, c.Body) }
+		var suggestion string
+		switch {
+		case isRecv(op.Comm) && fails(timeout) && !fails(op):
+			suggestion = before + "AssertRecv"
+		case isRecv(op.Comm) && fails(op) && !fails(timeout):
+			suggestion = before + "AssertNoRecv"
+		case isSend(op.Comm) && fails(timeout) && !fails(op):
+			suggestion = before + "AssertSend"
+		case isSend(op.Comm) && fails(op) && !fails(timeout):
+			suggestion = before + "AssertNoSend"
+		default:
+			return
+		}
+		pass.Reportf(sel.Pos(), "select with a time.After timeout can be replaced by %s", suggestion)
+	})
+	return nil, nil
+}
+
+// isTimeAfter returns the duration argument of comm if it's a receive from a
+// time.After call.
+func isTimeAfter(pass *analysis.Pass, comm ast.Stmt) ast.Expr {
+	var x ast.Expr
+	switch comm := comm.(type) {
+	case *ast.ExprStmt:
+		x = comm.X
+	case *ast.AssignStmt:
+		if len(comm.Rhs) == 1 {
+			x = comm.Rhs[0]
+		}
+	}
+	recv, ok := x.(*ast.UnaryExpr)
+	if !ok || recv.Op != token.ARROW {
+		return nil
+	}
+	call, ok := recv.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "time" || fn.Name() != "After" {
+		return nil
+	}
+	return call.Args[0]
+}
+
+func isRecv(comm ast.Stmt) bool {
+	var x ast.Expr
+	switch comm := comm.(type) {
+	case *ast.ExprStmt:
+		x = comm.X
+	case *ast.AssignStmt:
+		x = comm.Rhs[0]
+	}
+	recv, ok := x.(*ast.UnaryExpr)
+	return ok && recv.Op == token.ARROW
+}
+
+func isSend(comm ast.Stmt) bool {
+	_, ok := comm.(*ast.SendStmt)
+	return ok
+}
+
+// failsTest reports whether stmts call a method that fails a test, like
+// t.Fatal.
+func failsTest(pass *analysis.Pass, stmts []ast.Stmt) bool {
+	fails := false
+	for _, s := range stmts {
+		ast.Inspect(s, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return !fails
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+				switch sel.Sel.Name {
+				case "Fatal", "Fatalf", "FailNow", "Error", "Errorf", "Fail":
+					fails = true
+				}
+			}
+			return !fails
+		})
+	}
+	return fails
+}
+
+// render returns the source of d, unless it's chantest's default timeout,
+// which needs no Before.
+func render(pass *analysis.Pass, d ast.Expr) string {
+	if tv, ok := pass.TypesInfo.Types[d]; ok && tv.Value != nil && tv.Value.ExactString() == "100000000" {
+		return ""
+	}
+	return types.ExprString(d)
+}
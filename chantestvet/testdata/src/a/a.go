@@ -0,0 +1,41 @@
+package a
+
+import (
+	"testing"
+	"time"
+
+	"github.com/canastic/chantest"
+)
+
+func misuse(t *testing.T, a *chantest.Asserter, chs chan int, any interface{}) {
+	chantest.AssertRecv(t, chs)
+	chantest.AssertRecv(t, any)
+	chantest.AssertRecv(t, 42)                       // want `AssertRecv called with int, which isn't a channel`
+	chantest.AssertNoRecv(t, &chs)                   // want `AssertNoRecv called with \*chan int, which isn't a channel`
+	chantest.Before(time.Second).AssertRecv(t, "ch") // want `AssertRecv called with string, which isn't a channel`
+
+	chantest.AssertSend(t, chs, 1)
+	chantest.AssertSend(t, chs, "one") // want `AssertSend of string to a channel of int`
+	a.AssertSend(chs, 1.5)             // want `AssertSend of float64 to a channel of int`
+	chantest.AssertSend(t, chs, any)
+
+	const zero = 0
+	chantest.Before(zero).AssertRecv(t, chs) // want `Before\(0\) makes assertions time out right away`
+	chantest.Before(time.Millisecond).AssertRecv(t, chs)
+}
+
+func noSend(t *testing.T, a *chantest.Asserter) {
+	unbuffered := make(chan int)
+	chantest.AssertNoSend(t, unbuffered, 1) // want `AssertNoSend on an unbuffered channel`
+	a.AssertNoSend(unbuffered, 1)           // want `AssertNoSend on an unbuffered channel`
+
+	zeroCap := make(chan int, 0)
+	chantest.Before(time.Second).AssertNoSend(t, zeroCap, 1) // want `AssertNoSend on an unbuffered channel`
+
+	buffered := make(chan int, 1)
+	chantest.AssertNoSend(t, buffered, 1)
+
+	reassigned := make(chan int)
+	reassigned = make(chan int, 1)
+	chantest.AssertNoSend(t, reassigned, 1)
+}
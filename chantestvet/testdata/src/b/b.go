@@ -0,0 +1,15 @@
+package b
+
+import (
+	"testing"
+	"time"
+)
+
+// Not in a test file.
+func wait(t *testing.T, ch chan int) {
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -0,0 +1,49 @@
+package b
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelects(t *testing.T) {
+	ch := make(chan int)
+
+	select { // want `select with a time.After timeout can be replaced by chantest.Before\(time.Second\).AssertRecv`
+	case v := <-ch:
+		_ = v
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	select { // want `can be replaced by chantest.AssertRecv$`
+	case <-time.After(100 * time.Millisecond):
+		t.Fatalf("timeout")
+	case <-ch:
+	}
+
+	select { // want `can be replaced by chantest.Before\(10 \* time.Millisecond\).AssertNoRecv`
+	case <-ch:
+		t.Error("unexpected receive")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select { // want `can be replaced by chantest.Before\(time.Second\).AssertSend`
+	case ch <- 1:
+	case <-time.After(time.Second):
+		t.FailNow()
+	}
+
+	// Doing something else than failing on timeout.
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Log("no value, carrying on")
+	}
+
+	select {
+	case <-ch:
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
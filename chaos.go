@@ -0,0 +1,72 @@
+package chantest
+
+import (
+	"time"
+)
+
+// A Chaotic channel delays each value it relays by a random time, to shake out
+// assumptions about timing and ordering in the code around it.
+//
+// Like with an Instrumented channel, producers send to In and consumers
+// receive from Out, and the values are relayed through the channel's buffer.
+type Chaotic[T any] struct {
+	in  chan T
+	buf chan T
+	out chan T
+}
+
+// Chaos returns a Chaotic channel with ch as its buffer, which delays each
+// value by up to maxDelay both before putting it in the buffer, as a slow
+// send, and before handing it to a receiver, as a slow receive. ch must not be
+// used directly afterwards.
+//
+// Of opts, WithClock sets the clock delays are measured with, WithSeed makes
+// the delays the same on every run, and Perturb adds yields around them.
+func Chaos[T any](ch chan T, maxDelay time.Duration, opts ...Option) *Chaotic[T] {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	c := &Chaotic[T]{in: make(chan T), buf: ch, out: make(chan T)}
+	go func() {
+		delay, perturb := delayer(cfg, 0, maxDelay), cfg.perturber(2)
+		for v := range c.in {
+			delay()
+			perturb()
+			c.buf <- v
+		}
+		close(c.buf)
+	}()
+	go func() {
+		delay, perturb := delayer(cfg, 1, maxDelay), cfg.perturber(3)
+		for v := range c.buf {
+			delay()
+			perturb()
+			c.out <- v
+			perturb()
+		}
+		close(c.out)
+	}()
+	return c
+}
+
+// In returns the channel for producers to send to, and eventually close.
+func (c *Chaotic[T]) In() chan<- T {
+	return c.in
+}
+
+// Out returns the channel for consumers to receive from.
+func (c *Chaotic[T]) Out() <-chan T {
+	return c.out
+}
+
+// delayer returns a function that waits for a random time up to max, from a
+// generator seeded with the configured seed plus offset.
+func delayer(cfg config, offset int64, max time.Duration) func() {
+	r := cfg.rand(offset)
+	return func() {
+		if d := time.Duration(r.Int63n(int64(max) + 1)); d > 0 {
+			<-cfg.clock.After(d)
+		}
+	}
+}
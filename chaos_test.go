@@ -0,0 +1,34 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChaos(t *testing.T) {
+	c := Chaos(make(chan int, 10), 2*time.Millisecond, WithSeed(1))
+	for i := 0; i < 10; i++ {
+		AssertSend(t, c.In(), i)
+	}
+	close(c.In())
+	for i := 0; i < 10; i++ {
+		AssertRecvEqual(t, c.Out(), i)
+	}
+	Expect(t, func() {
+		if _, ok := <-c.Out(); ok {
+			t.Fatal("extra value received")
+		}
+	})
+}
+
+func TestChaosDelays(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	c := Chaos(make(chan int), time.Hour, WithClock(clock), WithSeed(1))
+	go func() { c.In() <- 1 }()
+	AssertNoRecv(New(t, Before(5*time.Millisecond)), c.Out())
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Hour)
+	}
+	AssertRecv(t, c.Out())
+}
@@ -0,0 +1,328 @@
+package chantest
+
+import (
+	"sync"
+	"time"
+)
+
+// A Clock measures the time assertions wait for.
+//
+// Fake clocks from other packages, like github.com/jonboulle/clockwork and
+// github.com/benbjohnson/clock, already implement Clock, so that assertions
+// can share the virtual timeline of the code under test. Wrapping them with
+// AdaptClock additionally stops the timers of assertions that are done early.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// A timerClock is a Clock whose timers can be stopped, so that they don't
+// linger after the assertion that started them is done.
+type timerClock interface {
+	Clock
+	startTimer(d time.Duration) (<-chan time.Time, func())
+}
+
+// startTimer starts a timer for d on clock, returning its channel and a
+// function that stops it.
+func startTimer(clock Clock, d time.Duration) (<-chan time.Time, func()) {
+	if clock, ok := clock.(timerClock); ok {
+		return clock.startTimer(d)
+	}
+	return clock.After(d), func() {}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (systemClock) startTimer(d time.Duration) (<-chan time.Time, func()) {
+	timer := time.NewTimer(d)
+	return timer.C, func() { timer.Stop() }
+}
+
+// A timerPool reuses stopped system timers, as tests may make many assertions
+// in a row and each one needs a timer.
+//
+// Timers created inside a testing/synctest bubble can't be used outside it,
+// and the other way around, so pools belong to an Asserter rather than being
+// global.
+type timerPool struct {
+	pool sync.Pool
+}
+
+func (p *timerPool) start(d time.Duration) timer {
+	t, _ := p.pool.Get().(*time.Timer)
+	if t == nil {
+		t = time.NewTimer(d)
+	} else {
+		t.Reset(d)
+	}
+	return timer{C: t.C, pooled: t, pool: p}
+}
+
+// A timer is a started assertion timeout. Unlike the pair returned by
+// startTimer, starting and stopping one from a timerPool doesn't allocate.
+type timer struct {
+	C <-chan time.Time
+
+	// Either pooled and pool, or stopFunc, are set.
+	pooled   *time.Timer
+	pool     *timerPool
+	stopFunc func()
+}
+
+func (tm timer) stop() {
+	if tm.pooled == nil {
+		tm.stopFunc()
+		return
+	}
+	if !tm.pooled.Stop() {
+		// It may have fired without anyone receiving.
+		select {
+		case <-tm.pooled.C:
+		default:
+		}
+	}
+	tm.pool.pool.Put(tm.pooled)
+}
+
+// AdaptClock returns a Clock from the functions of some other clock
+// implementation. afterFunc must call f once d elapses, unless the stop
+// function it returns is called first.
+//
+// With github.com/jonboulle/clockwork, for instance:
+//
+//	fc := clockwork.NewFakeClock()
+//	clock := chantest.AdaptClock(fc.Now, func(d time.Duration, f func()) func() bool {
+//		return fc.AfterFunc(d, f).Stop
+//	})
+//
+// Stopped timers are no longer counted by the fake clock, so that waiting for
+// the code under test to start some number of timers, as with clockwork's
+// BlockUntil, isn't thrown off by those of past assertions.
+func AdaptClock(now func() time.Time, afterFunc func(d time.Duration, f func()) (stop func() bool)) Clock {
+	return adaptedClock{now: now, afterFunc: afterFunc}
+}
+
+type adaptedClock struct {
+	now       func() time.Time
+	afterFunc func(d time.Duration, f func()) (stop func() bool)
+}
+
+func (c adaptedClock) Now() time.Time {
+	return c.now()
+}
+
+func (c adaptedClock) After(d time.Duration) <-chan time.Time {
+	ch, _ := c.startTimer(d)
+	return ch
+}
+
+func (c adaptedClock) startTimer(d time.Duration) (<-chan time.Time, func()) {
+	ch := make(chan time.Time, 1)
+	stop := c.afterFunc(d, func() { ch <- c.now() })
+	return ch, func() { stop() }
+}
+
+// A FakeClock is a Clock whose time only moves when Advance is called.
+//
+// With a FakeClock, negative assertions like AssertNoRecv are deterministic:
+// they don't pass until the test advances the clock past their Before duration.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+	// changed is closed and replaced whenever timers changes.
+	changed chan struct{}
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+	// period, if set, makes the timer a ticker's.
+	period time.Duration
+}
+
+// NewFakeClock returns a FakeClock whose time is now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that fires once the clock is advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	ch, _ := c.startTimer(d)
+	return ch
+}
+
+func (c *FakeClock) startTimer(d time.Duration) (<-chan time.Time, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch, func() {}
+	}
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), ch: ch})
+	c.notify()
+	return ch, func() { c.stop(ch) }
+}
+
+func (c *FakeClock) stop(ch chan time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, timer := range c.timers {
+		if timer.ch == ch {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.notify()
+			return
+		}
+	}
+}
+
+// Advance moves the clock forward by d, firing the timers that are due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		if timer.period == 0 {
+			timer.ch <- c.now
+			continue
+		}
+		// Like a time.Ticker's, the channel drops ticks for slow receivers.
+		select {
+		case timer.ch <- c.now:
+		default:
+		}
+		for !timer.at.After(c.now) {
+			timer.at = timer.at.Add(timer.period)
+		}
+		pending = append(pending, timer)
+	}
+	c.timers = pending
+	c.notify()
+}
+
+// FireNow fires the timer or ticker whose channel is ch as if it were due,
+// without moving the clock, and reports whether there was one. A fired timer
+// is done, while a ticker keeps its schedule.
+//
+// With it, a test can take a timeout path in the code under test without
+// advancing the clock for everything else.
+func (c *FakeClock) FireNow(ch <-chan time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, timer := range c.timers {
+		if (<-chan time.Time)(timer.ch) != ch {
+			continue
+		}
+		if timer.period > 0 {
+			select {
+			case timer.ch <- c.now:
+			default:
+			}
+			return true
+		}
+		timer.ch <- c.now
+		c.timers = append(c.timers[:i], c.timers[i+1:]...)
+		c.notify()
+		return true
+	}
+	return false
+}
+
+// next returns when the earliest timer is due, if any.
+func (c *FakeClock) next() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var at time.Time
+	for _, timer := range c.timers {
+		if at.IsZero() || timer.at.Before(at) {
+			at = timer.at
+		}
+	}
+	return at, !at.IsZero()
+}
+
+// BlockUntil blocks until at least n timers are waiting for the clock to
+// advance. Timers of assertions that are done are no longer waiting.
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mu.Lock()
+		waiting, changed := len(c.timers), c.changed
+		c.mu.Unlock()
+		if waiting >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+// notify must be called with c.mu held.
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// A FakeTicker is like a time.Ticker, but ticks as its FakeClock advances.
+// BlockUntil counts it as a waiting timer until it's stopped.
+type FakeTicker struct {
+	// C is the channel ticks are sent to.
+	C <-chan time.Time
+
+	clock *FakeClock
+	ch    chan time.Time
+}
+
+// NewTicker returns a FakeTicker that ticks whenever the clock advances past
+// a multiple of d from now. d must be positive.
+func (c *FakeClock) NewTicker(d time.Duration) *FakeTicker {
+	if d <= 0 {
+		panic("chantest: non-positive interval for FakeClock.NewTicker")
+	}
+	ch := make(chan time.Time, 1)
+	t := &FakeTicker{C: ch, clock: c, ch: ch}
+	t.start(d)
+	return t
+}
+
+func (t *FakeTicker) start(d time.Duration) {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), ch: t.ch, period: d})
+	c.notify()
+}
+
+// Stop stops the ticker. Like with time.Ticker, C isn't closed.
+func (t *FakeTicker) Stop() {
+	t.clock.stop(t.ch)
+}
+
+// Reset makes the ticker tick every d from now on.
+func (t *FakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("chantest: non-positive interval for FakeTicker.Reset")
+	}
+	t.clock.stop(t.ch)
+	t.start(d)
+}
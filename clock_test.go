@@ -0,0 +1,104 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	ch := make(chan int)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assertPasses(t, func(t TestingT) {
+			New(t, WithClock(clock), Before(time.Hour)).AssertNoRecv(ch)
+		})
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour - 1)
+	ExpectBlocked(t, func() { <-done })
+	clock.Advance(1)
+	AssertRecv(t, done)
+
+	if got, want := clock.Now(), time.Unix(0, 0).Add(time.Hour); !got.Equal(want) {
+		t.Fatalf("clock is at %v, want %v", got, want)
+	}
+}
+
+func TestBeforeKeepsAsserterClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	a := New(t, WithClock(clock))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assertFails(t, "timeout", func(t TestingT) {
+			ch := make(chan int)
+			Before(time.Minute).AssertRecv(New(t, WithClock(clock)), ch)
+		})
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	a.AssertRecv(done)
+}
+
+func TestFakeClockStopsDoneTimers(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	ch := make(chan int, 1)
+	ch <- 1
+	New(t, WithClock(clock)).AssertRecv(ch)
+
+	clock.mu.Lock()
+	pending := len(clock.timers)
+	clock.mu.Unlock()
+	if pending != 0 {
+		t.Fatalf("%d timers still pending after assertion", pending)
+	}
+}
+
+// afterFuncClock is a minimal fake clock in the style of those AdaptClock is
+// meant for.
+type afterFuncClock struct {
+	*FakeClock
+	stopped int
+}
+
+func (c *afterFuncClock) AfterFunc(d time.Duration, f func()) func() bool {
+	ch, stop := c.startTimer(d)
+	go func() {
+		if _, ok := <-ch; ok {
+			f()
+		}
+	}()
+	return func() bool {
+		c.stopped++
+		stop()
+		return true
+	}
+}
+
+func TestAdaptClock(t *testing.T) {
+	fc := &afterFuncClock{FakeClock: NewFakeClock(time.Unix(0, 0))}
+	clock := AdaptClock(fc.Now, fc.AfterFunc)
+	a := New(t, WithClock(clock), Before(time.Hour))
+
+	ch := make(chan int, 1)
+	ch <- 1
+	a.AssertRecv(ch)
+	if fc.stopped != 1 {
+		t.Fatalf("expected assertion timer to be stopped, got %d stops", fc.stopped)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.AssertNoRecv(ch)
+	}()
+	fc.BlockUntil(1)
+	fc.Advance(time.Hour)
+	AssertRecv(t, done)
+}
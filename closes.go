@@ -0,0 +1,63 @@
+package chantest
+
+import (
+	"fmt"
+	"time"
+)
+
+// AssertCloses runs do, concurrently, and asserts that ch quickly gets
+// closed, without any values received from it first. The failure lists the
+// values received, if any.
+func AssertCloses[T any](t TestingT, ch <-chan T, do func(), msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	go do()
+	tm := a.startTimer()
+	defer tm.stop()
+	var received []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if ok {
+				a.matched(ch, v)
+				received = append(received, v)
+				continue
+			}
+			if len(received) > 0 {
+				a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("channel closed after receiving unexpected values %#v", received), msgAndArgs...))
+			}
+			return
+		case <-tm.C:
+			msg := "timeout waiting for channel to be closed"
+			if len(received) > 0 {
+				msg += fmt.Sprintf("; received %#v", received)
+			}
+			a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+			return
+		}
+	}
+}
+
+// RecvAllUntilClosed receives every value from ch until it's closed, and
+// returns them, failing if that takes longer than deadline, as measured by the
+// Asserter's clock. The failure lists the values received so far.
+func RecvAllUntilClosed[T any](t TestingT, ch <-chan T, deadline time.Duration, msgAndArgs ...interface{}) []T {
+	a := asserterFor(t)
+	a.t.Helper()
+	timeout, stop := startTimer(a.clock, deadline)
+	defer stop()
+	var received []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return received
+			}
+			a.matched(ch, v)
+			received = append(received, v)
+		case <-timeout:
+			a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("channel not closed within %v; received %#v", deadline, received), msgAndArgs...))
+			return received
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package chantest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAssertCloses(t *testing.T) {
+	ch := make(chan int, 2)
+	assertPasses(t, func(t TestingT) {
+		AssertCloses(t, ch, func() { close(ch) })
+	})
+
+	ch = make(chan int, 2)
+	assertFails(t, "channel closed after receiving unexpected values []int{1, 2}", func(t TestingT) {
+		AssertCloses(t, ch, func() {
+			ch <- 1
+			ch <- 2
+			close(ch)
+		})
+	})
+
+	ch = make(chan int, 2)
+	assertFails(t, "timeout waiting for channel to be closed; received []int{1}", func(t TestingT) {
+		AssertCloses(New(t, short), ch, func() { ch <- 1 })
+	})
+	assertFails(t, "not closed", func(t TestingT) {
+		AssertCloses(New(t, short), make(chan int), func() {}, "not closed")
+	})
+}
+
+func TestRecvAllUntilClosed(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 1; i <= 3; i++ {
+			ch <- i
+		}
+	}()
+	if got := RecvAllUntilClosed(t, ch, time.Second); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Fatalf("got %v", got)
+	}
+
+	ch = make(chan int, 1)
+	ch <- 1
+	assertFails(t, "channel not closed within 10ms; received []int{1}", func(t TestingT) {
+		RecvAllUntilClosed(t, ch, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,146 @@
+// Command chantestgen generates typed chantest assertions with domain-specific
+// names, like AssertRecvOrder, for types of the package in the current
+// directory. They're concrete wrappers around chantest's reflection-based
+// assertions, so code that can't use type parameters still gets typed values
+// back, without type assertions at call sites.
+//
+// Usage:
+//
+//	//go:generate chantestgen -type Order,Refund
+//
+// For each type T, it generates AssertRecvT, AssertNoRecvT, AssertSendT and
+// AssertNoSendT into chantest_gen_test.go, or the file set by -output.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("chantestgen: ")
+	types := flag.String("type", "", "comma-separated list of type names; required")
+	output := flag.String("output", "chantest_gen_test.go", "output file name")
+	flag.Parse()
+	if *types == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := generate(".", strings.Split(*types, ","), strings.HasSuffix(*output, "_test.go"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the source of the assertions for the named types, which
+// must be declared in the package in dir. Test files are only considered if
+// the output is one too.
+func generate(dir string, types []string, test bool) ([]byte, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi os.FileInfo) bool {
+		return test || !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	var pkg *ast.Package
+	for name, p := range pkgs {
+		// An external test package can't see unexported types, and its
+		// declarations would be in the other package anyway.
+		if !strings.HasSuffix(name, "_test") {
+			pkg = p
+		}
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("no package in %s", dir)
+	}
+
+	declared := map[string]bool{}
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				for _, spec := range gd.Specs {
+					declared[spec.(*ast.TypeSpec).Name.Name] = true
+				}
+			}
+		}
+	}
+	var missing []string
+	for _, t := range types {
+		if !declared[t] {
+			missing = append(missing, t)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("types not declared in package %s: %s", pkg.Name, strings.Join(missing, ", "))
+	}
+
+	type typ struct {
+		// Name is the type's name as it appears in function names, which is
+		// capitalized, like in AssertRecvRefund for refund.
+		Type, Name string
+	}
+	data := struct {
+		Args    string
+		Package string
+		Types   []typ
+	}{Args: strings.Join(types, ","), Package: pkg.Name}
+	for _, t := range types {
+		data.Types = append(data.Types, typ{Type: t, Name: strings.ToUpper(t[:1]) + t[1:]})
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var tmpl = template.Must(template.New("").Parse(`// Code generated by "chantestgen -type {{.Args}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/canastic/chantest"
+{{range .Types}}
+// AssertRecv{{.Name}} asserts that a value is quickly received from ch, and returns it.
+func AssertRecv{{.Name}}(t chantest.TestingT, ch <-chan {{.Type}}, msgAndArgs ...interface{}) {{.Type}} {
+	chantest.Unwrap(t).Helper()
+	v, _ := chantest.AssertRecv(t, ch, msgAndArgs...).({{.Type}})
+	return v
+}
+
+// AssertNoRecv{{.Name}} asserts that nothing is received from ch for a very short
+// period of time. If something is, it's returned.
+func AssertNoRecv{{.Name}}(t chantest.TestingT, ch <-chan {{.Type}}, msgAndArgs ...interface{}) {{.Type}} {
+	chantest.Unwrap(t).Helper()
+	v, _ := chantest.AssertNoRecv(t, ch, msgAndArgs...).({{.Type}})
+	return v
+}
+
+// AssertSend{{.Name}} asserts that v is quickly sent to ch.
+func AssertSend{{.Name}}(t chantest.TestingT, ch chan<- {{.Type}}, v {{.Type}}, msgAndArgs ...interface{}) {
+	chantest.Unwrap(t).Helper()
+	chantest.AssertSend(t, ch, v, msgAndArgs...)
+}
+
+// AssertNoSend{{.Name}} asserts that v is not sent to ch for a very short period of
+// time.
+func AssertNoSend{{.Name}}(t chantest.TestingT, ch chan<- {{.Type}}, v {{.Type}}, msgAndArgs ...interface{}) {
+	chantest.Unwrap(t).Helper()
+	chantest.AssertNoSend(t, ch, v, msgAndArgs...)
+}
+{{end}}`))
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	got, err := generate("testdata/orders", []string{"Order", "refund"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("testdata/orders/chantest_gen_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("generated:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGeneratedCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	// The golden file, which TestGenerate compares with, is used by the
+	// package's own test.
+	if out, err := exec.Command("go", "test", "./testdata/orders").CombinedOutput(); err != nil {
+		t.Fatalf("go test of generated code failed: %v\n%s", err, out)
+	}
+}
+
+func TestGenerateMissingTypes(t *testing.T) {
+	_, err := generate("testdata/orders", []string{"Order", "Shipment", "Invoice"}, false)
+	if err == nil || !strings.Contains(err.Error(), "types not declared in package orders: Invoice, Shipment") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Code generated by "chantestgen -type Order,refund"; DO NOT EDIT.
+
+package orders
+
+import "github.com/canastic/chantest"
+
+// AssertRecvOrder asserts that a value is quickly received from ch, and returns it.
+func AssertRecvOrder(t chantest.TestingT, ch <-chan Order, msgAndArgs ...interface{}) Order {
+	chantest.Unwrap(t).Helper()
+	v, _ := chantest.AssertRecv(t, ch, msgAndArgs...).(Order)
+	return v
+}
+
+// AssertNoRecvOrder asserts that nothing is received from ch for a very short
+// period of time. If something is, it's returned.
+func AssertNoRecvOrder(t chantest.TestingT, ch <-chan Order, msgAndArgs ...interface{}) Order {
+	chantest.Unwrap(t).Helper()
+	v, _ := chantest.AssertNoRecv(t, ch, msgAndArgs...).(Order)
+	return v
+}
+
+// AssertSendOrder asserts that v is quickly sent to ch.
+func AssertSendOrder(t chantest.TestingT, ch chan<- Order, v Order, msgAndArgs ...interface{}) {
+	chantest.Unwrap(t).Helper()
+	chantest.AssertSend(t, ch, v, msgAndArgs...)
+}
+
+// AssertNoSendOrder asserts that v is not sent to ch for a very short period of
+// time.
+func AssertNoSendOrder(t chantest.TestingT, ch chan<- Order, v Order, msgAndArgs ...interface{}) {
+	chantest.Unwrap(t).Helper()
+	chantest.AssertNoSend(t, ch, v, msgAndArgs...)
+}
+
+// AssertRecvRefund asserts that a value is quickly received from ch, and returns it.
+func AssertRecvRefund(t chantest.TestingT, ch <-chan refund, msgAndArgs ...interface{}) refund {
+	chantest.Unwrap(t).Helper()
+	v, _ := chantest.AssertRecv(t, ch, msgAndArgs...).(refund)
+	return v
+}
+
+// AssertNoRecvRefund asserts that nothing is received from ch for a very short
+// period of time. If something is, it's returned.
+func AssertNoRecvRefund(t chantest.TestingT, ch <-chan refund, msgAndArgs ...interface{}) refund {
+	chantest.Unwrap(t).Helper()
+	v, _ := chantest.AssertNoRecv(t, ch, msgAndArgs...).(refund)
+	return v
+}
+
+// AssertSendRefund asserts that v is quickly sent to ch.
+func AssertSendRefund(t chantest.TestingT, ch chan<- refund, v refund, msgAndArgs ...interface{}) {
+	chantest.Unwrap(t).Helper()
+	chantest.AssertSend(t, ch, v, msgAndArgs...)
+}
+
+// AssertNoSendRefund asserts that v is not sent to ch for a very short period of
+// time.
+func AssertNoSendRefund(t chantest.TestingT, ch chan<- refund, v refund, msgAndArgs ...interface{}) {
+	chantest.Unwrap(t).Helper()
+	chantest.AssertNoSend(t, ch, v, msgAndArgs...)
+}
@@ -0,0 +1,10 @@
+package orders
+
+type Order struct {
+	ID    int
+	Items []string
+}
+
+type refund struct {
+	OrderID int
+}
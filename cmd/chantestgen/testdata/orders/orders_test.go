@@ -0,0 +1,25 @@
+package orders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/canastic/chantest"
+)
+
+func TestGenerated(t *testing.T) {
+	a := chantest.New(t, chantest.Before(10*time.Millisecond))
+	orders := make(chan Order, 1)
+	AssertSendOrder(a, orders, Order{ID: 1})
+	AssertNoSendOrder(a, orders, Order{ID: 2})
+	if got := AssertRecvOrder(a, orders); got.ID != 1 {
+		t.Fatalf("received order %d, want 1", got.ID)
+	}
+	AssertNoRecvOrder(a, orders)
+
+	refunds := make(chan refund, 1)
+	AssertSendRefund(t, refunds, refund{OrderID: 1})
+	if got := AssertRecvRefund(t, refunds); got.OrderID != 1 {
+		t.Fatalf("received refund of order %d, want 1", got.OrderID)
+	}
+}
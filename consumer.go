@@ -0,0 +1,85 @@
+package chantest
+
+import "fmt"
+
+// A Consumer is a script of expected receives from a channel, built with
+// Consume and checked with Run.
+type Consumer[T any] struct {
+	ch    <-chan T
+	steps []func(a *Asserter, v T, ok bool) string
+	// wants describes each step's expectation, for timeouts.
+	wants []string
+}
+
+// Consume returns an empty Consumer script for ch.
+//
+//	chantest.Consume(ch).Expect(v1).ExpectMatch(isValid).ExpectClose().Run(t)
+func Consume[T any](ch <-chan T) *Consumer[T] {
+	return &Consumer[T]{ch: ch}
+}
+
+// Expect adds receiving a value equal to want, as per reflect.DeepEqual unless
+// otherwise set with WithEqual.
+func (c *Consumer[T]) Expect(want T) *Consumer[T] {
+	return c.add(fmt.Sprintf("%#v", want), func(a *Asserter, v T, ok bool) string {
+		if !ok {
+			return fmt.Sprintf("channel closed, expected %#v", want)
+		}
+		if !a.equals(v, want) {
+			return fmt.Sprintf("received %#v, expected %#v", v, want)
+		}
+		return ""
+	})
+}
+
+// ExpectMatch adds receiving a value for which match returns true.
+func (c *Consumer[T]) ExpectMatch(match func(T) bool) *Consumer[T] {
+	return c.add("a matching value", func(_ *Asserter, v T, ok bool) string {
+		if !ok {
+			return "channel closed, expected a matching value"
+		}
+		if !match(v) {
+			return fmt.Sprintf("received %#v, which doesn't match", v)
+		}
+		return ""
+	})
+}
+
+// ExpectClose adds the channel being closed.
+func (c *Consumer[T]) ExpectClose() *Consumer[T] {
+	return c.add("close", func(_ *Asserter, v T, ok bool) string {
+		if ok {
+			return fmt.Sprintf("received %#v, expected close", v)
+		}
+		return ""
+	})
+}
+
+func (c *Consumer[T]) add(want string, step func(a *Asserter, v T, ok bool) string) *Consumer[T] {
+	c.steps = append(c.steps, step)
+	c.wants = append(c.wants, want)
+	return c
+}
+
+// Run receives from the channel for each step in turn, failing the test at the
+// first step that isn't met, or whose receive doesn't happen quickly.
+//
+// Run returns when done; to run the script alongside the test, call it from
+// Go.
+func (c *Consumer[T]) Run(t TestingT, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	for i, step := range c.steps {
+		v, ok, received := recvOrClose(a, c.ch)
+		var msg string
+		if !received {
+			msg = "timeout waiting for " + c.wants[i]
+		} else {
+			msg = step(a, v, ok)
+		}
+		if msg != "" {
+			a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("step %d: %s", i+1, msg), msgAndArgs...))
+			return
+		}
+	}
+}
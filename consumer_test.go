@@ -0,0 +1,34 @@
+package chantest
+
+import "testing"
+
+func TestConsume(t *testing.T) {
+	even := func(n int) bool { return n%2 == 0 }
+	feed := func(values ...int) <-chan int {
+		ch := make(chan int, len(values))
+		for _, v := range values {
+			ch <- v
+		}
+		close(ch)
+		return ch
+	}
+
+	assertPasses(t, func(t TestingT) {
+		Consume(feed(1, 2)).Expect(1).ExpectMatch(even).ExpectClose().Run(t)
+	})
+	assertFails(t, "step 2: received 3, which doesn't match", func(t TestingT) {
+		Consume(feed(1, 3)).Expect(1).ExpectMatch(even).Run(t)
+	})
+	assertFails(t, "step 1: received 2, expected 1", func(t TestingT) {
+		Consume(feed(2)).Expect(1).Run(t)
+	})
+	assertFails(t, "step 2: channel closed, expected 2", func(t TestingT) {
+		Consume(feed(1)).Expect(1).Expect(2).Run(t)
+	})
+	assertFails(t, "step 2: received 2, expected close", func(t TestingT) {
+		Consume(feed(1, 2)).Expect(1).ExpectClose().Run(t)
+	})
+	assertFails(t, "step 1: timeout waiting for a matching value", func(t TestingT) {
+		Consume(make(chan int)).ExpectMatch(even).Run(New(t, short))
+	})
+}
@@ -0,0 +1,200 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A Controller runs a script of expected channel interactions, in the style of
+// gomock: expectations are declared upfront, and Finish then performs them and
+// verifies that they all happen.
+//
+// Expectations are unordered, unless ordered with Then or Call.After, so that
+// a script can be partially ordered. Finish performs any of the expectations
+// whose predecessors are done, as the code under test is ready for them.
+type Controller struct {
+	s *plan
+	// after, if set, is the predecessor of the next expectation.
+	after *Call
+}
+
+type plan struct {
+	t     TestingT
+	calls []*Call
+	done  bool
+}
+
+// A Call is an expected interaction declared on a Controller.
+type Call struct {
+	c     *Controller
+	n     int
+	dir   reflect.SelectDir
+	ch    reflect.Value
+	v     interface{}
+	after []*Call
+	done  bool
+}
+
+func (c *Call) String() string {
+	if c.dir == reflect.SelectSend {
+		return fmt.Sprintf("#%d send %#v to %s", c.n, c.v, c.ch.Type())
+	}
+	return fmt.Sprintf("#%d recv %#v from %s", c.n, c.v, c.ch.Type())
+}
+
+// NewController returns a Controller with an empty script. If t has a Cleanup
+// method, like *testing.T, Finish is registered with it.
+func NewController(t TestingT) *Controller {
+	c := &Controller{s: &plan{t: t}}
+	if cl, ok := unwrap(t).(cleanuper); ok {
+		cl.Cleanup(c.Finish)
+	}
+	return c
+}
+
+// ExpectRecv expects a value equal to want, as per reflect.DeepEqual unless
+// otherwise set with WithEqual on the Controller's TestingT, to be received
+// from ch, which must be a channel.
+func (c *Controller) ExpectRecv(ch, want interface{}) *Call {
+	return c.add(reflect.SelectRecv, ch, want)
+}
+
+// ExpectSend expects v to be sent to ch, which must be a channel, meaning
+// that the code under test receives it.
+func (c *Controller) ExpectSend(ch, v interface{}) *Call {
+	return c.add(reflect.SelectSend, ch, v)
+}
+
+func (c *Controller) add(dir reflect.SelectDir, ch, v interface{}) *Call {
+	call := &Call{c: c, n: len(c.s.calls) + 1, dir: dir, ch: reflect.ValueOf(ch), v: v}
+	if c.after != nil {
+		call.after = append(call.after, c.after)
+	}
+	c.s.calls = append(c.s.calls, call)
+	return call
+}
+
+// Then returns a Controller on the same script whose next expectation
+// happens after c.
+func (c *Call) Then() *Controller {
+	return &Controller{s: c.c.s, after: c}
+}
+
+// After makes c happen after each of calls, and returns c.
+func (c *Call) After(calls ...*Call) *Call {
+	c.after = append(c.after, calls...)
+	return c
+}
+
+// Finish performs the script, failing the test if an expectation isn't
+// ready within t's timeout once its predecessors are done, or if an
+// unexpected value is received. Once Finish has run, further calls do nothing.
+func (c *Controller) Finish() {
+	a := asserterFor(c.s.t)
+	a.t.Helper()
+	if c.s.done {
+		return
+	}
+	c.s.done = true
+
+	for {
+		ready := c.s.ready()
+		if len(ready) == 0 {
+			if len(c.s.finished()) < len(c.s.calls) {
+				a.t.Fatal("expectations ordered after each other in a cycle; done: " + callList(c.s.finished()))
+			}
+			return
+		}
+
+		// Receives on the same channel share a case, and the value received
+		// picks the expectation it's for.
+		var cases []reflect.SelectCase
+		var caseCalls [][]*Call
+		recvCase := map[uintptr]int{}
+		for _, call := range ready {
+			if call.dir == reflect.SelectRecv {
+				if i, ok := recvCase[call.ch.Pointer()]; ok {
+					caseCalls[i] = append(caseCalls[i], call)
+					continue
+				}
+				recvCase[call.ch.Pointer()] = len(cases)
+			}
+			cases = append(cases, reflect.SelectCase{Dir: call.dir, Chan: call.ch})
+			if call.dir == reflect.SelectSend {
+				cases[len(cases)-1].Send = reflect.ValueOf(call.v)
+			}
+			caseCalls = append(caseCalls, []*Call{call})
+		}
+
+		timeout, stop := a.timeout()
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)})
+		chosen, recv, recvOK := reflect.Select(cases)
+		stop()
+		if chosen == len(cases)-1 {
+			a.t.Fatal(fmt.Sprintf("timeout waiting for %s; done: %s", callList(ready), callList(c.s.finished())))
+			return
+		}
+
+		calls := caseCalls[chosen]
+		if calls[0].dir == reflect.SelectSend {
+			calls[0].done = true
+			continue
+		}
+		if !recvOK {
+			a.t.Fatal(fmt.Sprintf("channel %s closed; expected %s", calls[0].ch.Type(), callList(calls)))
+			return
+		}
+		a.matched(calls[0].ch.Interface(), recv.Interface())
+		matched := false
+		for _, call := range calls {
+			if a.equals(recv.Interface(), call.v) {
+				call.done, matched = true, true
+				break
+			}
+		}
+		if !matched {
+			a.t.Fatal(fmt.Sprintf("unexpected %#v received from %s; expected %s", recv.Interface(), calls[0].ch.Type(), callList(calls)))
+			return
+		}
+	}
+}
+
+// ready returns the calls that aren't done and whose predecessors are.
+func (s *plan) ready() []*Call {
+	var ready []*Call
+	for _, call := range s.calls {
+		if call.done {
+			continue
+		}
+		blocked := false
+		for _, prev := range call.after {
+			blocked = blocked || !prev.done
+		}
+		if !blocked {
+			ready = append(ready, call)
+		}
+	}
+	return ready
+}
+
+func (s *plan) finished() []*Call {
+	var finished []*Call
+	for _, call := range s.calls {
+		if call.done {
+			finished = append(finished, call)
+		}
+	}
+	return finished
+}
+
+func callList(calls []*Call) string {
+	if len(calls) == 0 {
+		return "none"
+	}
+	s := make([]string, len(calls))
+	for i, call := range calls {
+		s[i] = call.String()
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,100 @@
+package chantest
+
+import "testing"
+
+func TestController(t *testing.T) {
+	requests, responses := make(chan string), make(chan int)
+	go func() {
+		for req := range requests {
+			responses <- len(req)
+		}
+	}()
+	defer close(requests)
+
+	m := NewController(t)
+	m.ExpectSend(requests, "hello").Then().ExpectRecv(responses, 5).Then().ExpectSend(requests, "bye").Then().ExpectRecv(responses, 3)
+	m.Finish()
+	m.Finish()
+}
+
+func TestControllerPartialOrder(t *testing.T) {
+	a, b := make(chan int, 2), make(chan int, 2)
+	go func() {
+		b <- 2
+		a <- 1
+		b <- 3
+	}()
+
+	m := NewController(t)
+	first := m.ExpectRecv(a, 1)
+	m.ExpectRecv(b, 3)
+	m.ExpectRecv(b, 2)
+	m.ExpectSend(a, 4).After(first)
+	m.Finish()
+	if got := Recv(t, a); got != 4 {
+		t.Fatalf("got %d, want 4", got)
+	}
+}
+
+func TestControllerFailures(t *testing.T) {
+	assertFails(t, "unexpected 2 received from chan int; expected #1 recv 1 from chan int", func(t TestingT) {
+		ch := make(chan int, 1)
+		ch <- 2
+		m := NewController(t)
+		m.ExpectRecv(ch, 1).Then().ExpectRecv(ch, 2)
+		m.Finish()
+	})
+	assertFails(t, "timeout waiting for #3 send 2 to chan int; done: #1 recv 1 from chan int, #2 send 1 to chan int", func(t TestingT) {
+		ch := make(chan int, 1)
+		ch <- 1
+		m := NewController(New(t, short))
+		m.ExpectRecv(ch, 1).Then().ExpectSend(ch, 1).Then().ExpectSend(ch, 2)
+		m.Finish()
+	})
+	assertFails(t, "channel chan int closed; expected #1 recv 1 from chan int", func(t TestingT) {
+		ch := make(chan int)
+		close(ch)
+		m := NewController(t)
+		m.ExpectRecv(ch, 1)
+		m.Finish()
+	})
+	assertFails(t, "expectations ordered after each other in a cycle; done: none", func(t TestingT) {
+		ch := make(chan int)
+		m := NewController(t)
+		first := m.ExpectRecv(ch, 1)
+		first.After(m.ExpectRecv(ch, 2).After(first))
+		m.Finish()
+	})
+}
+
+func TestControllerCleanup(t *testing.T) {
+	ch := make(chan int, 1)
+	t.Run("finish", func(t *testing.T) {
+		NewController(t).ExpectSend(ch, 1)
+	})
+	if got := Recv(t, ch); got != 1 {
+		t.Fatalf("got %d, want 1", got)
+	}
+
+	t.Run("finish with Asserter", func(t *testing.T) {
+		NewController(New(t)).ExpectSend(ch, 2)
+	})
+	if got := Recv(t, ch); got != 2 {
+		t.Fatalf("got %d, want 2", got)
+	}
+}
+
+func TestControllerStrict(t *testing.T) {
+	a := New(t, Strict())
+	jobs := Instrument(make(chan string, 1))
+	a.Register("jobs", jobs)
+	jobs.In() <- "build"
+
+	c := NewController(a)
+	c.ExpectRecv(jobs.Out(), "build")
+	c.Finish()
+	jobs.AssertRecvCount(t, 1)
+	assertPasses(t, func(t TestingT) {
+		rebind(t, a).Verify()
+	})
+}
@@ -0,0 +1,134 @@
+package chantest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mermaid renders tr as a Mermaid sequence diagram, with a lane for each
+// channel between the senders on the left and the receivers on the right.
+func (tr Trace) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("sequenceDiagram\n    participant senders\n")
+	lanes := tr.lanes()
+	for i, name := range lanes.names {
+		fmt.Fprintf(&b, "    participant c%d as %s\n", i, mermaidEscape(name))
+	}
+	b.WriteString("    participant receivers\n")
+	for _, e := range tr {
+		lane := lanes.index[e.Channel]
+		switch e.Kind {
+		case EventSend:
+			fmt.Fprintf(&b, "    senders->>c%d: #%d %s\n", lane, e.Seq, mermaidEscape(fmt.Sprintf("%#v", e.Value)))
+		case EventRecv:
+			fmt.Fprintf(&b, "    c%d->>receivers: #%d %s\n", lane, e.Seq, mermaidEscape(fmt.Sprintf("%#v", e.Value)))
+		case EventClose:
+			fmt.Fprintf(&b, "    Note over c%d: #%d closed\n", lane, e.Seq)
+		}
+	}
+	return b.String()
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer("#", "#35;", ";", "#59;", "\n", " ").Replace(s)
+}
+
+// Graphviz renders tr as a Graphviz digraph, with a cluster for each channel
+// holding its events in order, and an edge from each send to the receive of
+// the same value.
+func (tr Trace) Graphviz() string {
+	var b strings.Builder
+	b.WriteString("digraph trace {\n    rankdir=TB;\n    node [shape=box];\n")
+	lanes := tr.lanes()
+	for i, name := range lanes.names {
+		fmt.Fprintf(&b, "    subgraph cluster_%d {\n        label=%q;\n", i, name)
+		prev := -1
+		for _, e := range tr.Channel(name) {
+			label := fmt.Sprintf("#%d %s", e.Seq, e.Kind)
+			if e.Kind != EventClose {
+				label += fmt.Sprintf(" %#v", e.Value)
+			}
+			fmt.Fprintf(&b, "        e%d [label=%q];\n", e.Seq, label)
+			if prev >= 0 {
+				fmt.Fprintf(&b, "        e%d -> e%d [style=dotted, arrowhead=none];\n", prev, e.Seq)
+			}
+			prev = e.Seq
+		}
+		b.WriteString("    }\n")
+	}
+	for _, name := range lanes.names {
+		// Channels are FIFO, so the nth receive gets the nth send.
+		sends, recvs := tr.Channel(name).Kind(EventSend), tr.Channel(name).Kind(EventRecv)
+		for i := 0; i < len(sends) && i < len(recvs); i++ {
+			fmt.Fprintf(&b, "    e%d -> e%d;\n", sends[i].Seq, recvs[i].Seq)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+type lanes struct {
+	names []string
+	index map[string]int
+}
+
+// lanes returns the trace's channels in order of first appearance.
+func (tr Trace) lanes() lanes {
+	l := lanes{index: map[string]int{}}
+	for _, e := range tr {
+		if _, ok := l.index[e.Channel]; !ok {
+			l.index[e.Channel] = len(l.names)
+			l.names = append(l.names, e.Channel)
+		}
+	}
+	return l
+}
+
+// WriteDiagram writes tr as a Graphviz diagram if path ends in .dot or .gv,
+// and as a Mermaid diagram otherwise. It returns the path written to.
+//
+// If path is empty, the diagram is written as trace.mmd in t's TempDir, if it
+// has one, like *testing.T, or in a new temporary directory otherwise. The
+// path is logged if t has a Log method.
+func (tr Trace) WriteDiagram(t TestingT, path string) string {
+	t = unwrap(t)
+	t.Helper()
+	if path == "" {
+		dir, err := tempDir(t)
+		if err != nil {
+			t.Fatal(fmt.Sprintf("creating directory for trace diagram: %v", err))
+			return ""
+		}
+		path = filepath.Join(dir, "trace.mmd")
+	}
+
+	diagram := tr.Mermaid()
+	if ext := filepath.Ext(path); ext == ".dot" || ext == ".gv" {
+		diagram = tr.Graphviz()
+	}
+	if err := os.WriteFile(path, []byte(diagram), 0o644); err != nil {
+		t.Fatal(fmt.Sprintf("writing trace diagram: %v", err))
+		return ""
+	}
+	if l, ok := t.(logger); ok {
+		l.Log("trace diagram written to " + path)
+	}
+	return path
+}
+
+type tempDirer interface {
+	TempDir() string
+}
+
+type logger interface {
+	Log(...interface{})
+}
+
+func tempDir(t TestingT) (string, error) {
+	if td, ok := t.(tempDirer); ok {
+		return td.TempDir(), nil
+	}
+	return os.MkdirTemp("", "chantest")
+}
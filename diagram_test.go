@@ -0,0 +1,81 @@
+package chantest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var diagramTrace = Trace{
+	{Seq: 0, Channel: "orders", Kind: EventSend, Value: "a;b"},
+	{Seq: 1, Channel: "acks", Kind: EventSend, Value: 1},
+	{Seq: 2, Channel: "orders", Kind: EventRecv, Value: "a;b"},
+	{Seq: 3, Channel: "orders", Kind: EventClose},
+}
+
+func TestMermaid(t *testing.T) {
+	want := `sequenceDiagram
+    participant senders
+    participant c0 as orders
+    participant c1 as acks
+    participant receivers
+    senders->>c0: #0 "a#59;b"
+    senders->>c1: #1 1
+    c0->>receivers: #2 "a#59;b"
+    Note over c0: #3 closed
+`
+	if got := diagramTrace.Mermaid(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGraphviz(t *testing.T) {
+	got := diagramTrace.Graphviz()
+	for _, want := range []string{
+		`subgraph cluster_0 {
+        label="orders";
+        e0 [label="#0 send \"a;b\""];
+        e2 [label="#2 recv \"a;b\""];
+        e0 -> e2 [style=dotted, arrowhead=none];
+        e3 [label="#3 close"];
+        e2 -> e3 [style=dotted, arrowhead=none];
+    }`,
+		`e1 [label="#1 send 1"];`,
+		"    e0 -> e2;\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("diagram doesn't contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "e1 -> ") {
+		t.Fatalf("unreceived send has an edge:\n%s", got)
+	}
+}
+
+func TestWriteDiagram(t *testing.T) {
+	path := diagramTrace.WriteDiagram(t, "")
+	if filepath.Base(path) != "trace.mmd" {
+		t.Fatalf("unexpected path %q", path)
+	}
+	assertFileContains(t, path, "sequenceDiagram")
+
+	path = diagramTrace.WriteDiagram(t, filepath.Join(t.TempDir(), "trace.dot"))
+	assertFileContains(t, path, "digraph trace")
+
+	missing := filepath.Join(t.TempDir(), "missing", "trace.mmd")
+	assertFails(t, "writing trace diagram", func(t TestingT) {
+		diagramTrace.WriteDiagram(t, missing)
+	})
+}
+
+func assertFileContains(t *testing.T, path, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), want) {
+		t.Fatalf("%s doesn't contain %q:\n%s", path, want, got)
+	}
+}
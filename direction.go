@@ -0,0 +1,13 @@
+package chantest
+
+// Split returns send-only and receive-only views of ch, for handing each to
+// the side of the code under test that should only use it that way, while the
+// test keeps ch to make assertions on either end.
+//
+//	ch := make(chan Event, 1)
+//	in, out := chantest.Split(ch)
+//	go produce(in)
+//	chantest.AssertRecv(t, out)
+func Split[T any](ch chan T) (send chan<- T, recv <-chan T) {
+	return ch, ch
+}
@@ -0,0 +1,18 @@
+package chantest
+
+import "testing"
+
+func TestSplit(t *testing.T) {
+	ch := make(chan int, 1)
+	send, recv := Split(ch)
+	go func(send chan<- int) {
+		send <- 1
+	}(send)
+	if got := AssertRecv(t, recv); got != 1 {
+		t.Fatalf("got %v", got)
+	}
+	AssertSend(t, ch, 2)
+	if got := <-recv; got != 2 {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,33 @@
+package chantest
+
+import "fmt"
+
+// DrainAndAssertQuiet receives the values immediately available from ch,
+// without waiting, and then asserts that nothing else is received for a very
+// short period of time, and returns the drained values. A closed channel
+// counts as quiet.
+//
+// Useful for checking that an operation produced some burst of output, and
+// then really stopped.
+func DrainAndAssertQuiet[T any](t TestingT, ch <-chan T, msgAndArgs ...interface{}) []T {
+	a := asserterFor(t)
+	a.t.Helper()
+	var drained []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return drained
+			}
+			a.matched(ch, v)
+			drained = append(drained, v)
+			continue
+		default:
+		}
+		break
+	}
+	if v, ok, received := recvOrClose(a, ch); received && ok {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("unexpected channel receive of %#v after draining %#v", v, drained), msgAndArgs...))
+	}
+	return drained
+}
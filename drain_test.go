@@ -0,0 +1,35 @@
+package chantest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDrainAndAssertQuiet(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	if got := DrainAndAssertQuiet(New(t, short), ch); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Fatalf("got %v", got)
+	}
+	if got := DrainAndAssertQuiet(New(t, short), ch); got != nil {
+		t.Fatalf("got %v", got)
+	}
+
+	ch <- 1
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ch <- 2
+	}()
+	assertFails(t, "unexpected channel receive of 2 after draining []int{1}", func(t TestingT) {
+		DrainAndAssertQuiet(t, ch)
+	})
+
+	ch <- 2
+	ch <- 3
+	close(ch)
+	if got := DrainAndAssertQuiet(t, ch); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Fatalf("got %v", got)
+	}
+}
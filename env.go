@@ -0,0 +1,37 @@
+package chantest
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// EnvBefore is the environment variable FromEnv reads an Asserter's Before
+// duration from, in the format of time.ParseDuration, e.g. "500ms".
+const EnvBefore = "CHANTEST_BEFORE"
+
+// FromEnv sets an Asserter's Before duration from EnvBefore, if it's set, so
+// that slow environments, like a loaded CI runner, can wait longer without
+// changing the tests. The variable is read each time the Option applies, that
+// is, by each New call, so every test gets an Asserter of its own rather than
+// sharing a package-level setting, and options after FromEnv still override
+// it.
+//
+//	a := chantest.New(t, chantest.FromEnv())
+//
+// New fails the test if the variable isn't a valid, non-negative duration,
+// even if later options override it.
+func FromEnv() Option {
+	return optionFunc(func(c *config) {
+		s, ok := os.LookupEnv(EnvBefore)
+		if !ok || s == "" {
+			return
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			c.invalid = fmt.Sprintf("invalid %s %q; want a non-negative duration, e.g. \"500ms\"", EnvBefore, s)
+			return
+		}
+		c.d = Before(d)
+	})
+}
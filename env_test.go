@@ -0,0 +1,30 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(EnvBefore, "")
+	if a := New(t, FromEnv()); a.d != Default {
+		t.Fatalf("Before is %v with no %s", a.d, EnvBefore)
+	}
+
+	t.Setenv(EnvBefore, "250ms")
+	if a := New(t, FromEnv()); a.d != Before(250*time.Millisecond) {
+		t.Fatalf("Before is %v, want 250ms", a.d)
+	}
+	if a := New(t, FromEnv(), short); a.d != short {
+		t.Fatalf("Before is %v, want it overridden to %v", a.d, short)
+	}
+
+	t.Setenv(EnvBefore, "soon")
+	assertFails(t, `invalid CHANTEST_BEFORE "soon"; want a non-negative duration`, func(t TestingT) {
+		New(t, FromEnv())
+	})
+	t.Setenv(EnvBefore, "-1s")
+	assertFails(t, `invalid CHANTEST_BEFORE "-1s"`, func(t TestingT) {
+		New(t, FromEnv(), short)
+	})
+}
@@ -0,0 +1,318 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// An EqualOption changes how received values are compared with the expected
+// ones in AssertRecvEqual.
+type EqualOption interface {
+	applyEqual(*equality)
+}
+
+type equalOptionFunc func(*equality)
+
+func (f equalOptionFunc) applyEqual(e *equality) { f(e) }
+
+// equality is the configuration of a comparison.
+type equality struct {
+	// ignore holds the paths of fields not to compare.
+	ignore map[string]bool
+	// fields holds the values fields must have instead of the expected ones,
+	// by path.
+	fields map[string]interface{}
+	// within, if set, is the tolerance for times and durations.
+	within *time.Duration
+	// diffFunc, if set, replaces the built-in comparison.
+	diffFunc func(got, want interface{}) string
+	// visited holds the references compare has followed, for cyclic values.
+	visited map[visit]bool
+}
+
+// A visit is a pair of references compared with each other.
+type visit struct {
+	got, want uintptr
+	typ       reflect.Type
+}
+
+func newEquality(opts []EqualOption) *equality {
+	e := &equality{ignore: map[string]bool{}, fields: map[string]interface{}{}}
+	for _, opt := range opts {
+		opt.applyEqual(e)
+	}
+	return e
+}
+
+// Ignore leaves the named struct fields out of comparisons, for volatile ones
+// like timestamps and UUIDs. Fields of nested structs are named by their path,
+// e.g. "Meta.Timestamp", which also covers that field in elements of slices,
+// arrays and maps.
+func Ignore(fields ...string) EqualOption {
+	return equalOptionFunc(func(e *equality) {
+		for _, f := range fields {
+			e.ignore[f] = true
+		}
+	})
+}
+
+// Field makes comparisons require the named struct field, named like in
+// Ignore, to equal v, as per reflect.DeepEqual, instead of the field of the
+// expected value.
+func Field(name string, v interface{}) EqualOption {
+	return equalOptionFunc(func(e *equality) { e.fields[name] = v })
+}
+
+// WithinDuration makes comparisons accept time.Time and time.Duration values,
+// at any depth, that are at most d apart, rather than exactly equal.
+func WithinDuration(d time.Duration) EqualOption {
+	return equalOptionFunc(func(e *equality) { e.within = &d })
+}
+
+// CompareWith replaces the built-in comparison, and the Ignore and Field
+// options, with diff, which returns an empty string for equal values, and a
+// description of their differences otherwise. It's meant for plugging in other
+// comparison libraries, like go-cmp with the chantestcmp package.
+func CompareWith(diff func(got, want interface{}) string) EqualOption {
+	return equalOptionFunc(func(e *equality) { e.diffFunc = diff })
+}
+
+// AssertRecvEqual asserts that a value is quickly received from ch and that it
+// equals want, and returns it. The failure lists the differing fields.
+//
+// Values are compared like with reflect.DeepEqual, unless otherwise set by
+// opts, except that values with an Equal method taking their own type, like
+// time.Time, are compared with it. opts are applied after those set with
+// WithEqual.
+func AssertRecvEqual[T any](t TestingT, ch <-chan T, want T, opts ...EqualOption) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	got, ok := recv(a, ch)
+	if !ok {
+		a.t.Fatal("timeout waiting for channel send or receive")
+		return got
+	}
+	if diffs := newEquality(append(a.equalOpts[:len(a.equalOpts):len(a.equalOpts)], opts...)).diff(got, want); len(diffs) > 0 {
+		a.t.Fatal(fmt.Sprintf("received %#v, want %#v; differences:\n\t%s", got, want, strings.Join(diffs, "\n\t")))
+	}
+	return got
+}
+
+// diff returns the differences between got and want.
+func (e *equality) diff(got, want interface{}) []string {
+	if e.diffFunc != nil {
+		if d := e.diffFunc(got, want); d != "" {
+			return []string{d}
+		}
+		return nil
+	}
+	var diffs []string
+	e.visited = nil
+	e.compare("", reflect.ValueOf(got), reflect.ValueOf(want), &diffs)
+	return diffs
+}
+
+// equals reports whether a received value got equals want, as configured by
+// WithEqual, or as per reflect.DeepEqual by default.
+func (a *Asserter) equals(got, want interface{}) bool {
+	if len(a.equalOpts) == 0 {
+		return reflect.DeepEqual(got, want)
+	}
+	return len(newEquality(a.equalOpts).diff(got, want)) == 0
+}
+
+var indexSegment = regexp.MustCompile(`\[[^]]*\]`)
+
+// compare appends the differences between got and want, at path, to diffs.
+func (e *equality) compare(path string, got, want reflect.Value, diffs *[]string) {
+	field := indexSegment.ReplaceAllString(path, "")
+	if e.ignore[field] {
+		return
+	}
+	if v, ok := e.fields[field]; ok {
+		if !got.IsValid() || !got.CanInterface() || !reflect.DeepEqual(got.Interface(), v) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: got %s, want %#v", pathName(path), format(got), v))
+		}
+		return
+	}
+
+	differ := func() {
+		*diffs = append(*diffs, fmt.Sprintf("%s: got %s, want %s", pathName(path), format(got), format(want)))
+	}
+	if !got.IsValid() || !want.IsValid() {
+		if got.IsValid() != want.IsValid() {
+			differ()
+		}
+		return
+	}
+	if got.Type() != want.Type() {
+		differ()
+		return
+	}
+
+	if e.within != nil {
+		if diff, ok := timeDiff(got, want); ok {
+			if diff < -*e.within || diff > *e.within {
+				differ()
+			}
+			return
+		}
+	}
+
+	if eq, ok := equalMethod(got); ok {
+		if !eq.Call([]reflect.Value{want})[0].Bool() {
+			differ()
+		}
+		return
+	}
+
+	switch got.Kind() {
+	case reflect.Struct:
+		for i := 0; i < got.NumField(); i++ {
+			e.compare(joinPath(path, got.Type().Field(i).Name), got.Field(i), want.Field(i), diffs)
+		}
+		return
+	case reflect.Ptr, reflect.Interface:
+		if got.IsNil() || want.IsNil() {
+			if got.IsNil() != want.IsNil() {
+				differ()
+			}
+			return
+		}
+		if got.Kind() == reflect.Ptr && e.seen(got, want) {
+			return
+		}
+		e.compare(path, got.Elem(), want.Elem(), diffs)
+		return
+	case reflect.Slice, reflect.Array:
+		if got.Kind() == reflect.Slice && got.IsNil() != want.IsNil() || got.Len() != want.Len() {
+			differ()
+			return
+		}
+		if got.Kind() == reflect.Slice && got.Len() > 0 && e.seen(got, want) {
+			return
+		}
+		for i := 0; i < got.Len(); i++ {
+			e.compare(fmt.Sprintf("%s[%d]", path, i), got.Index(i), want.Index(i), diffs)
+		}
+		return
+	case reflect.Map:
+		if got.IsNil() != want.IsNil() || got.Len() != want.Len() {
+			differ()
+			return
+		}
+		if !got.IsNil() && e.seen(got, want) {
+			return
+		}
+		for _, k := range want.MapKeys() {
+			g := got.MapIndex(k)
+			if !g.IsValid() {
+				*diffs = append(*diffs, fmt.Sprintf("%s[%s]: missing", pathName(path), format(k)))
+				continue
+			}
+			e.compare(fmt.Sprintf("%s[%s]", path, format(k)), g, want.MapIndex(k), diffs)
+		}
+		return
+	}
+	if !leafEqual(got, want) {
+		differ()
+	}
+}
+
+// seen records that compare got to the references got and want, and reports
+// whether it already had. Like with reflect.DeepEqual, a pair seen before
+// compares as equal, so that cyclic values don't recurse forever.
+func (e *equality) seen(got, want reflect.Value) bool {
+	v := visit{got.Pointer(), want.Pointer(), got.Type()}
+	if e.visited[v] {
+		return true
+	}
+	if e.visited == nil {
+		e.visited = map[visit]bool{}
+	}
+	e.visited[v] = true
+	return false
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// timeDiff returns got minus want if they're times or durations.
+func timeDiff(got, want reflect.Value) (time.Duration, bool) {
+	switch {
+	case got.Type() == durationType:
+		return time.Duration(got.Int() - want.Int()), true
+	case got.Type() == timeType && got.CanInterface() && want.CanInterface():
+		return got.Interface().(time.Time).Sub(want.Interface().(time.Time)), true
+	}
+	return 0, false
+}
+
+// equalMethod returns v's Equal method, if it has one taking a value of its
+// own type and returning a bool, like time.Time.
+func equalMethod(v reflect.Value) (reflect.Value, bool) {
+	if !v.CanInterface() {
+		return reflect.Value{}, false
+	}
+	m := v.MethodByName("Equal")
+	if !m.IsValid() {
+		return reflect.Value{}, false
+	}
+	mt := m.Type()
+	if mt.NumIn() != 1 || mt.In(0) != v.Type() || mt.NumOut() != 1 || mt.Out(0).Kind() != reflect.Bool {
+		return reflect.Value{}, false
+	}
+	return m, true
+}
+
+// leafEqual compares values of the same type that compare doesn't descend
+// into, including unexported ones, which can't be turned back into
+// interfaces.
+func leafEqual(got, want reflect.Value) bool {
+	if got.CanInterface() && want.CanInterface() {
+		return reflect.DeepEqual(got.Interface(), want.Interface())
+	}
+	switch got.Kind() {
+	case reflect.Bool:
+		return got.Bool() == want.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return got.Int() == want.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return got.Uint() == want.Uint()
+	case reflect.Float32, reflect.Float64:
+		return got.Float() == want.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return got.Complex() == want.Complex()
+	case reflect.String:
+		return got.String() == want.String()
+	default:
+		return got.Pointer() == want.Pointer()
+	}
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func pathName(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
+
+func format(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	// fmt formats the value held by a reflect.Value, even if unexported.
+	return fmt.Sprintf("%#v", v)
+}
@@ -0,0 +1,160 @@
+package chantest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type event struct {
+	ID        int
+	Name      string
+	Timestamp time.Time
+	Meta      eventMeta
+	Tags      []eventMeta
+	secret    string
+}
+
+type eventMeta struct {
+	UUID string
+	Seq  int
+}
+
+func TestAssertRecvEqual(t *testing.T) {
+	got := event{
+		ID:        42,
+		Name:      "created",
+		Timestamp: time.Now(),
+		Meta:      eventMeta{UUID: "f00", Seq: 1},
+		Tags:      []eventMeta{{UUID: "a", Seq: 1}, {UUID: "b", Seq: 2}},
+		secret:    "s",
+	}
+	want := event{
+		ID:     42,
+		Name:   "created",
+		Meta:   eventMeta{Seq: 1},
+		Tags:   []eventMeta{{Seq: 1}, {Seq: 2}},
+		secret: "s",
+	}
+	ch := make(chan event, 1)
+
+	ch <- got
+	assertPasses(t, func(t TestingT) {
+		AssertRecvEqual(t, ch, want, Ignore("Timestamp", "Meta.UUID", "Tags.UUID"))
+	})
+	ch <- got
+	assertPasses(t, func(t TestingT) {
+		AssertRecvEqual(t, ch, event{}, Field("ID", 42), Field("Name", "created"), Ignore("Timestamp", "Meta", "Tags", "secret"))
+	})
+
+	ch <- got
+	assertFails(t, "differences:\n\tTimestamp: got time.Date(", func(t TestingT) {
+		AssertRecvEqual(t, ch, want, Ignore("Meta", "Tags"))
+	})
+	ch <- got
+	assertFails(t, "differences:\n\tID: got 42, want 7", func(t TestingT) {
+		AssertRecvEqual(t, ch, event{}, Field("ID", 7), Ignore("Name", "Timestamp", "Meta", "Tags", "secret"))
+	})
+	got.secret = "t"
+	ch <- got
+	assertFails(t, "\n\tsecret: got \"t\", want \"s\"", func(t TestingT) {
+		AssertRecvEqual(t, ch, event{ID: 42, Name: "created", Tags: want.Tags[:1], secret: "s"}, Ignore("Timestamp", "Meta"))
+	})
+	ch <- got
+	assertFails(t, "differences:\n\tTags: got []chantest.eventMeta{", func(t TestingT) {
+		AssertRecvEqual(t, ch, event{ID: 42, Name: "created", Tags: want.Tags[:1], secret: "t"}, Ignore("Timestamp", "Meta"))
+	})
+	assertFails(t, "timeout waiting for channel send or receive", func(t TestingT) {
+		AssertRecvEqual(New(t, short), ch, want)
+	})
+}
+
+func TestAssertRecvEqualNonStruct(t *testing.T) {
+	ch := make(chan map[string][]int, 1)
+	ch <- map[string][]int{"a": {1, 2}}
+	assertFails(t, `differences:
+	["a"][1]: got 2, want 3`, func(t TestingT) {
+		AssertRecvEqual(t, ch, map[string][]int{"a": {1, 3}})
+	})
+	var nilErr error
+	errs := make(chan error, 1)
+	errs <- nilErr
+	AssertRecvEqual(t, errs, nil)
+}
+
+func TestWithEqual(t *testing.T) {
+	caseless := CompareWith(func(got, want interface{}) string {
+		if !strings.EqualFold(got.(string), want.(string)) {
+			return fmt.Sprintf("%q isn't %q in any case", got, want)
+		}
+		return ""
+	})
+
+	ch := make(chan string, 2)
+	ch <- "Hello"
+	assertPasses(t, func(t TestingT) {
+		AssertRecvEqual(t, ch, "hello", caseless)
+	})
+	ch <- "Hello"
+	ch <- "Bye"
+	assertFails(t, "step 2: received \"Bye\", expected \"hi\"", func(t TestingT) {
+		Consume(ch).Expect("hello").Expect("hi").Run(New(t, WithEqual(caseless)))
+	})
+
+	ch <- "Hello"
+	assertFails(t, "differences:\n\t\"Hello\" isn't \"hi\" in any case", func(t TestingT) {
+		AssertRecvEqual(New(t, WithEqual(caseless)), ch, "hi")
+	})
+
+	in, out := make(chan string), make(chan string)
+	go func() {
+		defer close(out)
+		for v := range in {
+			out <- v + "!"
+		}
+	}()
+	Pipeline[string, string]{In: in, Out: out}.AssertOutputs(New(t, WithEqual(caseless)), []string{"a", "B"}, []string{"A!", "b!"})
+}
+
+func TestWithinDuration(t *testing.T) {
+	type timed struct {
+		At   time.Time
+		Took time.Duration
+	}
+	now := time.Now()
+	ch := make(chan timed, 1)
+
+	ch <- timed{At: now.Add(40 * time.Millisecond), Took: 90 * time.Millisecond}
+	assertPasses(t, func(t TestingT) {
+		AssertRecvEqual(t, ch, timed{At: now, Took: 100 * time.Millisecond}, WithinDuration(50*time.Millisecond))
+	})
+	ch <- timed{At: now.Add(-60 * time.Millisecond), Took: 100 * time.Millisecond}
+	assertFails(t, "differences:\n\tAt: got time.Date(", func(t TestingT) {
+		AssertRecvEqual(t, ch, timed{At: now, Took: 100 * time.Millisecond}, WithinDuration(50*time.Millisecond))
+	})
+	ch <- timed{At: now, Took: 200 * time.Millisecond}
+	assertFails(t, "differences:\n\tTook: got 200000000, want 100000000", func(t TestingT) {
+		AssertRecvEqual(t, ch, timed{At: now, Took: 100 * time.Millisecond}, WithinDuration(50*time.Millisecond))
+	})
+}
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestAssertRecvEqualCyclic(t *testing.T) {
+	cycle := func(name string) *node {
+		n := &node{Name: name}
+		n.Next = n
+		return n
+	}
+	ch := make(chan *node, 1)
+	ch <- cycle("a")
+	AssertRecvEqual(t, ch, cycle("a"))
+	ch <- cycle("a")
+	assertFails(t, "differences:\n\tName: got \"a\", want \"b\"", func(t TestingT) {
+		AssertRecvEqual(t, ch, cycle("b"))
+	})
+}
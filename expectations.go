@@ -0,0 +1,148 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Expectations collects expected receives, declared upfront, which are
+// satisfied as the scenario runs, in any order, and verified all together by
+// VerifyAll.
+//
+// Unlike a Controller, which receives as it performs its script, Expectations
+// receive from each channel as soon as the first expectation on it is
+// declared, so values sent before that count as unexpected.
+type Expectations struct {
+	a    *Asserter
+	stop chan struct{}
+
+	mu         sync.Mutex
+	watched    map[uintptr]bool
+	unmet      []expectation
+	unexpected []string
+	done       bool
+	// changed is closed and replaced whenever unmet changes.
+	changed chan struct{}
+}
+
+type expectation struct {
+	ch   reflect.Value
+	want interface{}
+}
+
+func (e expectation) String() string {
+	return fmt.Sprintf("recv %#v from %s", e.want, e.ch.Type())
+}
+
+// NewExpectations returns empty Expectations for t. If t has a Cleanup method,
+// like *testing.T, VerifyAll is registered with it.
+func NewExpectations(t TestingT) *Expectations {
+	e := &Expectations{
+		a:       asserterFor(t),
+		stop:    make(chan struct{}),
+		watched: map[uintptr]bool{},
+		changed: make(chan struct{}),
+	}
+	if c, ok := e.a.t.(cleanuper); ok {
+		c.Cleanup(func() { e.VerifyAll() })
+	}
+	return e
+}
+
+// ExpectRecv expects a value equal to want, as per reflect.DeepEqual unless
+// otherwise set with WithEqual on the Expectations' TestingT, to be received
+// from ch, which must be a channel, before VerifyAll.
+func (e *Expectations) ExpectRecv(ch, want interface{}) {
+	v := reflect.ValueOf(ch)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.unmet = append(e.unmet, expectation{ch: v, want: want})
+	if !e.watched[v.Pointer()] {
+		e.watched[v.Pointer()] = true
+		go e.watch(v)
+	}
+}
+
+// watch receives from ch until it's closed or VerifyAll is done, matching
+// each value with the first unmet expectation it's equal to.
+func (e *Expectations) watch(ch reflect.Value) {
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(e.stop)},
+	}
+	for {
+		chosen, recv, recvOK := reflect.Select(cases)
+		if chosen != 0 || !recvOK {
+			return
+		}
+		e.match(ch, recv.Interface())
+	}
+}
+
+func (e *Expectations) match(ch reflect.Value, got interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, exp := range e.unmet {
+		if exp.ch.Pointer() == ch.Pointer() && e.a.equals(got, exp.want) {
+			e.unmet = append(e.unmet[:i], e.unmet[i+1:]...)
+			e.a.matched(ch.Interface(), got)
+			close(e.changed)
+			e.changed = make(chan struct{})
+			return
+		}
+	}
+	e.unexpected = append(e.unexpected, fmt.Sprintf("%#v from %s", got, ch.Type()))
+}
+
+// VerifyAll waits for the Asserter's timeout at most for every expectation to
+// be met, stops receiving, and fails with a report of the unmet expectations
+// and the unexpected values received, if any. Calls after the first do
+// nothing.
+func (e *Expectations) VerifyAll(msgAndArgs ...interface{}) {
+	a := e.a
+	a.t.Helper()
+	e.mu.Lock()
+	done := e.done
+	e.done = true
+	e.mu.Unlock()
+	if done {
+		return
+	}
+
+	timeout, stop := a.timeout()
+	defer stop()
+wait:
+	for {
+		e.mu.Lock()
+		n, changed := len(e.unmet), e.changed
+		e.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			break wait
+		}
+	}
+	close(e.stop)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var problems []string
+	if len(e.unmet) > 0 {
+		unmet := make([]string, len(e.unmet))
+		for i, exp := range e.unmet {
+			unmet[i] = exp.String()
+		}
+		problems = append(problems, "unmet: "+strings.Join(unmet, ", "))
+	}
+	if len(e.unexpected) > 0 {
+		problems = append(problems, "unexpected: "+strings.Join(e.unexpected, ", "))
+	}
+	if len(problems) > 0 {
+		a.t.Fatal(defaultOrCustomMessage("expectations not met; "+strings.Join(problems, "; "), msgAndArgs...))
+	}
+}
@@ -0,0 +1,49 @@
+package chantest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpectations(t *testing.T) {
+	ints, strs := make(chan int), make(chan string)
+	e := NewExpectations(t)
+	e.ExpectRecv(ints, 1)
+	e.ExpectRecv(ints, 2)
+	e.ExpectRecv(strs, "a")
+	go func() {
+		strs <- "a"
+		ints <- 2
+		ints <- 1
+	}()
+	e.VerifyAll()
+	e.VerifyAll()
+
+	assertFails(t, `expectations not met; unmet: recv 2 from chan int, recv "b" from chan string; unexpected: 3 from chan int`, func(t TestingT) {
+		ints, strs := make(chan int), make(chan string)
+		e := NewExpectations(New(t, short))
+		e.ExpectRecv(ints, 1)
+		e.ExpectRecv(ints, 2)
+		e.ExpectRecv(strs, "b")
+		ints <- 3
+		ints <- 1
+		e.VerifyAll()
+	})
+}
+
+func TestExpectationsCleanup(t *testing.T) {
+	ct := &cleanupT{}
+	NewExpectations(New(ct, short)).ExpectRecv(make(chan int), 1)
+	if len(ct.cleanups) != 1 {
+		t.Fatalf("expected 1 cleanup, got %d", len(ct.cleanups))
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ct.cleanups[0]()
+	}()
+	<-done
+	if want := "unmet: recv 1 from chan int"; !ct.failed || !strings.Contains(ct.msg, want) {
+		t.Fatalf("got cleanup failure %q, want %q", ct.msg, want)
+	}
+}
@@ -0,0 +1,80 @@
+package chantest
+
+// An Expecter makes Expect assertions in a row with a reused goroutine, rather
+// than starting a new one for each, for tests that make a great many of them.
+//
+// An Expecter must not be used concurrently. Call Close once done with it.
+type Expecter struct {
+	a *Asserter
+	w *expectWorker
+}
+
+// NewExpecter returns an Expecter for t. If t has a Cleanup method, like
+// *testing.T, Close is registered with it.
+func NewExpecter(t TestingT) *Expecter {
+	e := &Expecter{a: asserterFor(t)}
+	if c, ok := e.a.t.(cleanuper); ok {
+		c.Cleanup(e.Close)
+	}
+	return e
+}
+
+// Expect is like Asserter.Expect, but runs do in the Expecter's goroutine.
+func (e *Expecter) Expect(do func()) {
+	e.a.t.Helper()
+	if e.w == nil {
+		e.w = startExpectWorker()
+	}
+	w := e.w
+	w.work <- do
+
+	timeout, stop := e.a.timeout()
+	defer stop()
+	select {
+	case returned := <-w.done:
+		if !returned {
+			// do stopped the goroutine, e.g. with runtime.Goexit.
+			e.w = nil
+		}
+	case <-timeout:
+		// Let the blocked goroutine exit whenever do returns.
+		close(w.work)
+		e.w = nil
+		e.a.t.Fatal("timeout waiting for channel send or receive")
+	}
+}
+
+// Close stops the Expecter's goroutine.
+func (e *Expecter) Close() {
+	if e.w != nil {
+		close(e.w.work)
+		e.w = nil
+	}
+}
+
+type expectWorker struct {
+	work chan func()
+	// done receives whether each do returned normally.
+	done chan bool
+}
+
+func startExpectWorker() *expectWorker {
+	w := &expectWorker{
+		work: make(chan func()),
+		done: make(chan bool, 1),
+	}
+	go func() {
+		for do := range w.work {
+			if !w.call(do) {
+				return
+			}
+		}
+	}()
+	return w
+}
+
+func (w *expectWorker) call(do func()) (returned bool) {
+	defer func() { w.done <- returned }()
+	do()
+	return true
+}
@@ -0,0 +1,64 @@
+package chantest
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestExpecter(t *testing.T) {
+	ch := make(chan int, 1)
+	assertPasses(t, func(t TestingT) {
+		e := NewExpecter(t)
+		defer e.Close()
+		for i := 0; i < 3; i++ {
+			ch <- i
+			e.Expect(func() { <-ch })
+		}
+		e.Expect(runtime.Goexit)
+		e.Expect(func() {})
+	})
+
+	assertFails(t, "timeout waiting for channel send or receive", func(t TestingT) {
+		e := NewExpecter(New(t, short))
+		defer e.Close()
+		e.Expect(func() { <-ch })
+	})
+}
+
+func TestExpecterRecoversAfterTimeout(t *testing.T) {
+	ch := make(chan int)
+	var e *Expecter
+	if _, failed := failure(func(t TestingT) {
+		e = NewExpecter(New(t, short))
+		e.Expect(func() { <-ch })
+	}); !failed {
+		t.Fatal("expected timeout")
+	}
+
+	close(ch)
+	e.a = New(t)
+	defer e.Close()
+	e.Expect(func() {})
+}
+
+func BenchmarkExpect(b *testing.B) {
+	ch := make(chan int, 1)
+	do := func() { <-ch }
+	b.Run("Expect", func(b *testing.B) {
+		a := New(b)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ch <- i
+			a.Expect(do)
+		}
+	})
+	b.Run("Expecter", func(b *testing.B) {
+		e := NewExpecter(New(b))
+		defer e.Close()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ch <- i
+			e.Expect(do)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package chantest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// An Action is a labeled operation for Explore to interleave.
+type Action struct {
+	Name string
+	Do   func()
+}
+
+// Explore runs the actions of a scenario in every order, or, if sample is
+// positive, in that many random orders, and fails with the first order after
+// which the scenario's check fails.
+//
+// For each order, scenario is called to set up fresh state, and returns the
+// actions and the check to run once they all return; it's also called once
+// beforehand to count the actions. The actions are started in order, each in
+// its own goroutine, waiting after each start until the action returns or
+// briefly blocks, so that actions that don't block run strictly in order.
+// They must all return quickly after the last one starts.
+//
+// The check's failure is captured, like with Go. Random orders come from
+// Asserter.Rand, so they're reproducible with WithSeed.
+//
+// To point at the actions whose order matters, the failure also reports the
+// shortest prefix of the failing order that fails when followed by the other
+// actions in the order they're declared in, if that's a different order.
+func Explore(t TestingT, sample int, scenario func() (actions []Action, check func(a *Asserter))) {
+	a := asserterFor(t)
+	a.t.Helper()
+	actions, _ := scenario()
+	var orders [][]int
+	if sample > 0 {
+		// Sampling is for when there are too many actions to list every order.
+		orders = make([][]int, sample)
+		r := a.Rand()
+		for i := range orders {
+			orders[i] = r.Perm(len(actions))
+		}
+	} else {
+		orders = permutations(len(actions))
+	}
+
+	for _, order := range orders {
+		names, failure := runOrder(a, scenario, order)
+		if failure == "" {
+			continue
+		}
+		msg := fmt.Sprintf("order [%s]: %s", strings.Join(names, " "), failure)
+		if prefix, shrunk, ok := shrinkOrder(a, scenario, order); ok {
+			msg += fmt.Sprintf("; shortest failing prefix [%s], as in order [%s]", strings.Join(prefix, " "), strings.Join(shrunk, " "))
+		}
+		a.t.Fatal(msg)
+		return
+	}
+}
+
+// shrinkOrder finds the shortest prefix of a failing order that still fails
+// when followed by the other actions in the order they're declared in. It
+// returns the prefix and the full order, unless that's just the failing one.
+func shrinkOrder(a *Asserter, scenario func() ([]Action, func(*Asserter)), order []int) (prefix, shrunk []string, ok bool) {
+	for k := 0; k < len(order)-1; k++ {
+		candidate := append([]int(nil), order[:k]...)
+		used := make([]bool, len(order))
+		for _, j := range candidate {
+			used[j] = true
+		}
+		for j := range order {
+			if !used[j] {
+				candidate = append(candidate, j)
+			}
+		}
+		if equalInts(candidate, order) {
+			return nil, nil, false
+		}
+		if names, failure := runOrder(a, scenario, candidate); failure != "" {
+			return names[:k], names, true
+		}
+	}
+	return nil, nil, false
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// runOrder sets up the scenario and runs its actions in order. It returns
+// their names in that order, and the failure, if any.
+func runOrder(a *Asserter, scenario func() ([]Action, func(*Asserter)), order []int) (names []string, failure string) {
+	actions, check := scenario()
+	names = make([]string, len(order))
+	for i, j := range order {
+		names[i] = actions[j].Name
+	}
+
+	done := make([]chan struct{}, len(actions))
+	for _, j := range order {
+		done[j] = make(chan struct{})
+		go func(action Action, done chan struct{}) {
+			defer close(done)
+			action.Do()
+		}(actions[j], done[j])
+		select {
+		case <-done[j]:
+		case <-time.After(a.pollInterval()):
+		}
+	}
+
+	tm := a.startTimer()
+wait:
+	for _, j := range order {
+		select {
+		case <-done[j]:
+		case <-tm.C:
+			break wait
+		}
+	}
+	tm.stop()
+	var pending []string
+	for _, j := range order {
+		select {
+		case <-done[j]:
+		default:
+			pending = append(pending, actions[j].Name)
+		}
+	}
+	if len(pending) > 0 {
+		return names, fmt.Sprintf("timeout waiting for %s to return", strings.Join(pending, ", "))
+	}
+
+	rec := &recordT{}
+	Go(&Asserter{t: rec, config: a.config}, check).Wait()
+	if rec.failure != nil {
+		return names, *rec.failure
+	}
+	return names, ""
+}
+
+// permutations returns every ordering of 0 to n-1, in lexicographic order.
+func permutations(n int) [][]int {
+	var perms [][]int
+	var extend func(perm []int, used []bool)
+	extend = func(perm []int, used []bool) {
+		if len(perm) == n {
+			perms = append(perms, append([]int(nil), perm...))
+			return
+		}
+		for i := 0; i < n; i++ {
+			if !used[i] {
+				used[i] = true
+				extend(append(perm, i), used)
+				used[i] = false
+			}
+		}
+	}
+	extend(make([]int, 0, n), make([]bool, n))
+	return perms
+}
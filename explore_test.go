@@ -0,0 +1,112 @@
+package chantest
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPermutations(t *testing.T) {
+	got := permutations(3)
+	want := [][]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestExplore(t *testing.T) {
+	// A cache that's only correct if it's filled before it's read.
+	scenario := func() ([]Action, func(a *Asserter)) {
+		cache := make(chan string, 1)
+		results := make(chan string, 1)
+		return []Action{
+			{Name: "fill", Do: func() { cache <- "hit" }},
+			{Name: "read", Do: func() {
+				select {
+				case v := <-cache:
+					results <- v
+				default:
+					results <- "miss"
+				}
+			}},
+		}, func(a *Asserter) {
+			AssertRecvEqual(a, results, "hit")
+		}
+	}
+	assertFails(t, `order [read fill]: received "miss", want "hit"`, func(t TestingT) {
+		Explore(t, 0, scenario)
+	})
+	assertFails(t, `order [read fill]: received "miss", want "hit"`, func(t TestingT) {
+		Explore(New(t, WithSeed(1)), 10, scenario)
+	})
+
+	withNoise := func() ([]Action, func(a *Asserter)) {
+		actions, check := scenario()
+		return append(actions, Action{Name: "noise", Do: func() {}}), check
+	}
+	prefix, shrunk, ok := shrinkOrder(New(t), withNoise, []int{1, 2, 0})
+	if !ok || !reflect.DeepEqual(prefix, []string{"read"}) || !reflect.DeepEqual(shrunk, []string{"read", "fill", "noise"}) {
+		t.Fatalf("shrunk to prefix %v, order %v, ok %v", prefix, shrunk, ok)
+	}
+	if _, _, ok := shrinkOrder(New(t), withNoise, []int{1, 0, 2}); ok {
+		t.Fatal("shrunk an already minimal order")
+	}
+
+	var orders [][]string
+	assertPasses(t, func(t TestingT) {
+		Explore(t, 0, func() ([]Action, func(a *Asserter)) {
+			var order []string
+			do := func(name string) Action {
+				return Action{Name: name, Do: func() { order = append(order, name) }}
+			}
+			return []Action{do("a"), do("b"), do("c")}, func(*Asserter) { orders = append(orders, order) }
+		})
+	})
+	if len(orders) != 6 || !reflect.DeepEqual(orders[3], []string{"b", "c", "a"}) {
+		t.Fatalf("unexpected orders: %v", orders)
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	assertFails(t, "order [a b]: timeout waiting for b to return", func(t TestingT) {
+		Explore(New(t, short), 0, func() ([]Action, func(a *Asserter)) {
+			return []Action{{Name: "a", Do: func() {}}, {Name: "b", Do: func() { <-block }}}, func(*Asserter) {}
+		})
+	})
+}
+
+func TestExploreSampleManyActions(t *testing.T) {
+	// 20! orders couldn't all be listed.
+	runs := 0
+	Explore(New(t, WithSeed(1)), 3, func() ([]Action, func(a *Asserter)) {
+		actions := make([]Action, 20)
+		for i := range actions {
+			actions[i] = Action{Name: string(rune('a' + i)), Do: func() {}}
+		}
+		return actions, func(*Asserter) { runs++ }
+	})
+	if runs != 3 {
+		t.Fatalf("checked %d orders, want 3", runs)
+	}
+}
+
+func TestExplorePollInterval(t *testing.T) {
+	// Each action gets the poll interval to return before the next starts.
+	var mu sync.Mutex
+	var order []string
+	do := func(name string, d time.Duration) Action {
+		return Action{Name: name, Do: func() {
+			time.Sleep(d)
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+		}}
+	}
+	_, failure := runOrder(New(t, WithPolling(PollOptions{Interval: time.Second})), func() ([]Action, func(*Asserter)) {
+		return []Action{do("a", 20*time.Millisecond), do("b", 0)}, func(*Asserter) {}
+	}, []int{0, 1})
+	if failure != "" || !reflect.DeepEqual(order, []string{"a", "b"}) {
+		t.Fatalf("ran %v, failure %q", order, failure)
+	}
+}
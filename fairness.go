@@ -0,0 +1,53 @@
+package chantest
+
+import (
+	"fmt"
+	"math"
+)
+
+// AssertUniform calls choose rounds times and asserts that each of the n
+// indices it returns is chosen about as often as the others, within tolerance
+// as a fraction of the expected count, e.g. 0.2 for ±20%.
+//
+// choose typically makes the code under test pick between n ready channels,
+// such as with a select, and returns which one it picked.
+func AssertUniform(t TestingT, n, rounds int, tolerance float64, choose func() int) {
+	unwrap(t).Helper()
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	AssertDistribution(t, weights, rounds, tolerance, choose)
+}
+
+// AssertDistribution is like AssertUniform, but for an index i expected to be
+// chosen in proportion to weights[i], as with a priority scheme.
+func AssertDistribution(t TestingT, weights []float64, rounds int, tolerance float64, choose func() int) {
+	t = unwrap(t)
+	t.Helper()
+	counts := make([]int, len(weights))
+	for r := 0; r < rounds; r++ {
+		i := choose()
+		if i < 0 || i >= len(counts) {
+			t.Fatal(fmt.Sprintf("choice %d out of range [0, %d)", i, len(counts)))
+			return
+		}
+		counts[i]++
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	for i, w := range weights {
+		want := float64(rounds) * w / total
+		if math.Abs(float64(counts[i])-want) > want*tolerance {
+			expected := make([]int, len(weights))
+			for j, w := range weights {
+				expected[j] = int(math.Round(float64(rounds) * w / total))
+			}
+			t.Fatal(fmt.Sprintf("choices distributed as %v over %d rounds; want about %v, within %v%%", counts, rounds, expected, tolerance*100))
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package chantest
+
+import "testing"
+
+func TestAssertUniform(t *testing.T) {
+	a, b, c := make(chan int, 1), make(chan int, 1), make(chan int, 1)
+	selectReady := func() int {
+		a <- 0
+		b <- 1
+		c <- 2
+		var chosen int
+		select {
+		case chosen = <-a:
+		case chosen = <-b:
+		case chosen = <-c:
+		}
+		for _, ch := range []chan int{a, b, c} {
+			select {
+			case <-ch:
+			default:
+			}
+		}
+		return chosen
+	}
+	AssertUniform(t, 3, 3000, 0.2, selectReady)
+
+	first := func() int {
+		a <- 0
+		b <- 1
+		defer func() { <-b }()
+		select {
+		case v := <-a:
+			return v
+		default:
+		}
+		return <-b
+	}
+	assertFails(t, "choices distributed as [1000 0] over 1000 rounds; want about [500 500], within 20%", func(t TestingT) {
+		AssertUniform(t, 2, 1000, 0.2, first)
+	})
+	AssertDistribution(t, []float64{1, 0}, 1000, 0.01, first)
+
+	assertFails(t, "choice 5 out of range [0, 2)", func(t TestingT) {
+		AssertUniform(t, 2, 1, 0.1, func() int { return 5 })
+	})
+}
@@ -0,0 +1,134 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// An Output is an output channel of a fan-out, with the values it should
+// receive.
+type Output[T any] struct {
+	Ch   <-chan T
+	Want []T
+}
+
+// AssertFanOut sends each of send to input, concurrently receiving from each
+// output until it has received as many values as it wants, and asserts that
+// each output receives its wanted values, in any order, as per
+// reflect.DeepEqual unless otherwise set with WithEqual.
+//
+// For a broadcast, every output wants every value; for a partition, each
+// wants its share. Outputs that stop receiving before the timeout, or get
+// closed, are reported with the values they're missing, and the unexpected
+// ones they got instead.
+func AssertFanOut[T any](t TestingT, input chan<- T, send []T, outputs ...Output[T]) {
+	a := asserterFor(t)
+	a.t.Helper()
+
+	abort := make(chan struct{})
+	defer close(abort)
+	sent := make(chan int, 1)
+	go func() {
+		n := 0
+		defer func() { sent <- n }()
+		for _, v := range send {
+			select {
+			case input <- v:
+				n++
+			case <-abort:
+				return
+			}
+		}
+	}()
+
+	got := make([][]T, len(outputs))
+	closed := make([]bool, len(outputs))
+	for {
+		var cases []reflect.SelectCase
+		var indices []int
+		for i, out := range outputs {
+			if !closed[i] && len(got[i]) < len(out.Want) {
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(out.Ch)})
+				indices = append(indices, i)
+			}
+		}
+		if len(cases) == 0 {
+			break
+		}
+		timeout, stop := a.timeout()
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)})
+		chosen, v, ok := reflect.Select(cases)
+		stop()
+		if chosen == len(cases)-1 {
+			break
+		}
+		i := indices[chosen]
+		if !ok {
+			closed[i] = true
+			continue
+		}
+		// A nil interface value, like a nil error, isn't a T to assert to.
+		var x T
+		if vx, ok := v.Interface().(T); ok {
+			x = vx
+		}
+		a.matched(outputs[i].Ch, x)
+		got[i] = append(got[i], x)
+	}
+
+	var misses []string
+	for i, out := range outputs {
+		missing, unexpected := multisetDiff(a, out.Want, got[i])
+		if len(missing) == 0 && len(unexpected) == 0 {
+			continue
+		}
+		miss := fmt.Sprintf("output %d: missing %v", i, missing)
+		if len(unexpected) > 0 {
+			miss += fmt.Sprintf(", unexpected %v", unexpected)
+		}
+		if closed[i] {
+			miss += ", closed"
+		}
+		misses = append(misses, miss)
+	}
+	if len(misses) > 0 {
+		a.t.Fatal("fan-out mismatch: " + strings.Join(misses, "; "))
+		return
+	}
+
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case n := <-sent:
+		if n < len(send) {
+			a.t.Fatal(fmt.Sprintf("only %d of %d values sent to input", n, len(send)))
+		}
+	case <-timeout:
+		a.t.Fatal(fmt.Sprintf("timeout sending %d values to input", len(send)))
+	}
+}
+
+// multisetDiff returns the values of want not in got, and the values of got
+// not in want, counting duplicates, comparing them with a.equals.
+func multisetDiff[T any](a *Asserter, want, got []T) (missing, unexpected []T) {
+	used := make([]bool, len(got))
+	for _, w := range want {
+		found := false
+		for j, g := range got {
+			if !used[j] && a.equals(g, w) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, w)
+		}
+	}
+	for j, g := range got {
+		if !used[j] {
+			unexpected = append(unexpected, g)
+		}
+	}
+	return missing, unexpected
+}
@@ -0,0 +1,70 @@
+package chantest
+
+import (
+	"errors"
+	"testing"
+)
+
+// partition sends even values to the first output and odd values to the
+// second, or, if broken, everything to the first.
+func partition(broken bool) (chan<- int, []<-chan int) {
+	in := make(chan int)
+	even, odd := make(chan int), make(chan int)
+	go func() {
+		for v := range in {
+			if v%2 == 0 || broken {
+				even <- v
+			} else {
+				odd <- v
+			}
+		}
+	}()
+	return in, []<-chan int{even, odd}
+}
+
+func TestAssertFanOut(t *testing.T) {
+	assertPasses(t, func(t TestingT) {
+		in, outs := partition(false)
+		AssertFanOut(t, in, []int{1, 2, 3, 4},
+			Output[int]{Ch: outs[0], Want: []int{2, 4}},
+			Output[int]{Ch: outs[1], Want: []int{3, 1}},
+		)
+	})
+	assertFails(t, "fan-out mismatch: output 0: missing [4], unexpected [1]; output 1: missing [1 3]", func(t TestingT) {
+		in, outs := partition(true)
+		AssertFanOut(New(t, short), in, []int{1, 2, 3, 4},
+			Output[int]{Ch: outs[0], Want: []int{2, 4}},
+			Output[int]{Ch: outs[1], Want: []int{1, 3}},
+		)
+	})
+	assertFails(t, "timeout sending 2 values to input", func(t TestingT) {
+		AssertFanOut(New(t, short), make(chan int), []int{1, 2})
+	})
+}
+
+func TestAssertFanOutBroadcast(t *testing.T) {
+	in := make(chan string)
+	outs := []chan string{make(chan string, 2), make(chan string, 2)}
+	go func() {
+		for v := range in {
+			for _, out := range outs {
+				out <- v
+			}
+		}
+	}()
+	AssertFanOut(t, in, []string{"a", "b"},
+		Output[string]{Ch: outs[0], Want: []string{"a", "b"}},
+		Output[string]{Ch: outs[1], Want: []string{"a", "b"}},
+	)
+}
+
+func TestAssertFanOutNil(t *testing.T) {
+	failed := errors.New("failed")
+	in, out := make(chan error), make(chan error, 2)
+	go func() {
+		for err := range in {
+			out <- err
+		}
+	}()
+	AssertFanOut(t, in, []error{nil, failed}, Output[error]{Ch: out, Want: []error{failed, nil}})
+}
@@ -0,0 +1,57 @@
+package chantest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fields are key-value pairs that, passed among the msgAndArgs of an
+// assertion, are appended to its failure message, whether it's the default
+// one or a custom one, as in "[queue=emails worker=3]". They identify the
+// case a failure is from, such as in a table-driven loop.
+//
+//	chantest.AssertRecv(t, ch, chantest.Fields{"worker": i, "queue": q})
+type Fields map[string]interface{}
+
+// String formats the fields as space-separated key=value pairs, sorted by
+// key, in brackets.
+func (f Fields) String() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, f[k])
+	}
+	return "[" + strings.Join(pairs, " ") + "]"
+}
+
+// splitFields takes the Fields out of msgAndArgs, merging them, and returns
+// the other arguments and the fields.
+func splitFields(msgAndArgs []interface{}) ([]interface{}, Fields) {
+	var args []interface{}
+	var fields Fields
+	for i, arg := range msgAndArgs {
+		f, ok := arg.(Fields)
+		if !ok {
+			if fields != nil {
+				args = append(args, arg)
+			}
+			continue
+		}
+		if fields == nil {
+			fields = Fields{}
+			args = append(args, msgAndArgs[:i]...)
+		}
+		for k, v := range f {
+			fields[k] = v
+		}
+	}
+	if fields == nil {
+		return msgAndArgs, nil
+	}
+	return args, fields
+}
@@ -0,0 +1,16 @@
+package chantest
+
+import "testing"
+
+func TestFields(t *testing.T) {
+	ch := make(chan int)
+	assertFails(t, "timeout waiting for channel send or receive [queue=emails worker=3]", func(t TestingT) {
+		AssertRecv(New(t, short), ch, Fields{"worker": 3, "queue": "emails"})
+	})
+	assertFails(t, "no job from worker 3 [attempt=2 queue=emails]", func(t TestingT) {
+		Recv(New(t, short), ch, "no job from worker %d", Fields{"queue": "emails"}, 3, Fields{"attempt": 2})
+	})
+	if got := defaultOrCustomMessage("default", "custom %d", 1); got != "custom 1" {
+		t.Fatalf("got %q", got)
+	}
+}
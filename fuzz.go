@@ -0,0 +1,40 @@
+package chantest
+
+import (
+	"fmt"
+	"testing"
+)
+
+// FuzzFeed runs f's fuzz target with the messages decode makes out of each
+// input, so that fuzzed bytes become typed messages for the code under test.
+// Inputs that decode rejects with an error are skipped.
+//
+// For each input, setup starts the code under test, and returns the channel to
+// feed it with, and check, which runs after each message has been sent and
+// the channel closed, with the messages, to assert on what the code did with
+// them. The messages must each be quickly received.
+//
+//	f.Add([]byte("a,b"))
+//	chantest.FuzzFeed(f, splitCommas, func(t *testing.T, a *chantest.Asserter) (chan<- string, func([]string)) {
+//		in, out := make(chan string), make(chan string)
+//		go uppercase(in, out)
+//		return in, func(sent []string) { ... }
+//	})
+func FuzzFeed[T any](f *testing.F, decode func(data []byte) ([]T, error), setup func(t *testing.T, a *Asserter) (ch chan<- T, check func(sent []T))) {
+	f.Helper()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msgs, err := decode(data)
+		if err != nil {
+			t.Skip(err)
+		}
+		a := New(t)
+		ch, check := setup(t, a)
+		for i, v := range msgs {
+			if !send(a, ch, v) {
+				t.Fatal(fmt.Sprintf("timeout sending message #%d %#v", i, v))
+			}
+		}
+		close(ch)
+		check(msgs)
+	})
+}
@@ -0,0 +1,38 @@
+package chantest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func FuzzFeedUppercase(f *testing.F) {
+	f.Add([]byte("a,b,c"))
+	f.Add([]byte("hello"))
+	f.Add([]byte(""))
+	split := func(data []byte) ([]string, error) {
+		if len(data) == 0 {
+			return nil, errors.New("empty input")
+		}
+		return strings.Split(string(data), ","), nil
+	}
+	FuzzFeed(f, split, func(t *testing.T, a *Asserter) (chan<- string, func([]string)) {
+		in, out := make(chan string), make(chan string, 100)
+		go func() {
+			defer close(out)
+			for s := range in {
+				out <- strings.ToUpper(s)
+			}
+		}()
+		return in, func(sent []string) {
+			for _, s := range sent {
+				AssertRecvEqual(a, out, strings.ToUpper(s))
+			}
+			Expect(a, func() {
+				if v, ok := <-out; ok {
+					t.Fatalf("unexpected output %q", v)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package chantest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// A Gate pauses goroutines, typically of the code under test, at a controlled
+// point until the test opens it.
+//
+// A Gate can be opened and closed any number of times. The zero value is not
+// usable; use NewGate.
+type Gate struct {
+	mu sync.Mutex
+	// opened is closed while the gate is open.
+	opened  chan struct{}
+	waiting int
+	// changed is closed and replaced whenever waiting changes.
+	changed chan struct{}
+}
+
+// NewGate returns a closed Gate.
+func NewGate() *Gate {
+	return &Gate{
+		opened:  make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+}
+
+// Open opens the gate, letting all current and future waiters through until
+// it's closed again.
+func (g *Gate) Open() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	select {
+	case <-g.opened:
+	default:
+		close(g.opened)
+	}
+}
+
+// Close closes the gate, so that future waiters block until it's open again.
+func (g *Gate) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	select {
+	case <-g.opened:
+		g.opened = make(chan struct{})
+	default:
+	}
+}
+
+// Wait blocks until the gate is open. It's meant to be called from the code
+// under test, through some hook.
+func (g *Gate) Wait() {
+	g.wait(nil)
+}
+
+// Pass is like Wait, but fails the test if the gate isn't open very quickly.
+func (g *Gate) Pass(t TestingT) {
+	a := asserterFor(t)
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	if !g.wait(timeout) {
+		a.t.Fatal("timeout waiting for gate to open")
+	}
+}
+
+// wait blocks until the gate is open or timeout fires, and reports whether the
+// gate was open.
+func (g *Gate) wait(timeout <-chan time.Time) bool {
+	g.mu.Lock()
+	opened := g.opened
+	g.setWaiting(g.waiting + 1)
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		g.setWaiting(g.waiting - 1)
+		g.mu.Unlock()
+	}()
+
+	select {
+	case <-opened:
+		return true
+	case <-timeout:
+		return false
+	}
+}
+
+// AssertWaiting asserts that exactly n goroutines are quickly blocked at the
+// gate.
+func (g *Gate) AssertWaiting(t TestingT, n int) {
+	a := asserterFor(t)
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	for {
+		g.mu.Lock()
+		waiting, changed := g.waiting, g.changed
+		g.mu.Unlock()
+		if waiting == n {
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			a.t.Fatal(fmt.Sprintf("timeout waiting for %d goroutines at gate; %d waiting", n, waiting))
+			return
+		}
+	}
+}
+
+// setWaiting must be called with g.mu held.
+func (g *Gate) setWaiting(n int) {
+	g.waiting = n
+	close(g.changed)
+	g.changed = make(chan struct{})
+}
+
+// A Latch is a one-shot gate that opens once it has been counted down a fixed
+// number of times, and then stays open.
+type Latch struct {
+	mu       sync.Mutex
+	count    int
+	released chan struct{}
+}
+
+// NewLatch returns a Latch that is released after n calls to CountDown.
+func NewLatch(n int) *Latch {
+	l := &Latch{count: n, released: make(chan struct{})}
+	if n <= 0 {
+		close(l.released)
+	}
+	return l
+}
+
+// CountDown decrements the latch's count, releasing it when it reaches zero.
+// Calls after the release have no effect.
+func (l *Latch) CountDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count <= 0 {
+		return
+	}
+	l.count--
+	if l.count == 0 {
+		close(l.released)
+	}
+}
+
+// Wait blocks until the latch is released. It's meant to be called from the
+// code under test, through some hook.
+func (l *Latch) Wait() {
+	<-l.released
+}
+
+// Pass is like Wait, but fails the test if the latch isn't released very
+// quickly.
+func (l *Latch) Pass(t TestingT) {
+	a := asserterFor(t)
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case <-l.released:
+	case <-timeout:
+		l.mu.Lock()
+		count := l.count
+		l.mu.Unlock()
+		a.t.Fatal(fmt.Sprintf("timeout waiting for latch release; count is %d", count))
+	}
+}
@@ -0,0 +1,44 @@
+package chantest
+
+import "testing"
+
+func TestGate(t *testing.T) {
+	g := NewGate()
+	a := func(t TestingT) *Asserter { return New(t, short) }
+
+	assertFails(t, "timeout waiting for gate to open", func(t TestingT) {
+		g.Pass(a(t))
+	})
+
+	assertPasses(t, func(t TestingT) {
+		go g.Wait()
+		go g.Wait()
+		g.AssertWaiting(t, 2)
+		g.Open()
+		g.AssertWaiting(t, 0)
+		g.Pass(t)
+		g.Close()
+		ExpectBlocked(a(t), g.Wait)
+	})
+
+	assertFails(t, "timeout waiting for 2 goroutines at gate; 1 waiting", func(t TestingT) {
+		g.AssertWaiting(a(t), 2)
+	})
+}
+
+func TestLatch(t *testing.T) {
+	l := NewLatch(2)
+	assertPasses(t, func(t TestingT) {
+		l.CountDown()
+		ExpectBlocked(New(t, short), l.Wait)
+	})
+	assertFails(t, "count is 1", func(t TestingT) {
+		l.Pass(New(t, short))
+	})
+	assertPasses(t, func(t TestingT) {
+		l.CountDown()
+		l.CountDown()
+		l.Pass(t)
+		Expect(t, l.Wait)
+	})
+}
@@ -0,0 +1,119 @@
+// Package golden compares chantest traces with golden files.
+//
+// Importing the package defines a -chantest.update flag for the test binary.
+// When set, AssertTrace writes traces to their golden files instead of
+// comparing them. The flag is namespaced so that it doesn't clash with the
+// -update flag tests often define for their own golden files:
+//
+//	go test ./... -chantest.update
+package golden
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/canastic/chantest"
+)
+
+var update = flag.Bool("chantest.update", false, "update chantest golden trace files")
+
+// An Option configures how a trace is written.
+type Option func(*options)
+
+type options struct {
+	round time.Duration
+}
+
+// WithTimes includes the time of each event relative to the first one,
+// rounded to a multiple of round so that it's stable across runs. By default,
+// times are left out.
+func WithTimes(round time.Duration) Option {
+	return func(o *options) { o.round = round }
+}
+
+// AssertTrace asserts that tr, formatted by Marshal, matches the contents of
+// the golden file at path, or writes it there if the -chantest.update flag is
+// set.
+//
+// The events in tr must happen in the same order on every run; with
+// concurrent producers, it's usually best to compare the trace of each
+// channel separately, as returned by Trace.Channel.
+func AssertTrace(t chantest.TestingT, tr chantest.Trace, path string, opts ...Option) {
+	t.Helper()
+	got := Marshal(tr, opts...)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(fmt.Sprintf("creating golden file directory: %v", err))
+			return
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(fmt.Sprintf("writing golden file: %v", err))
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("reading golden file: %v; run with -chantest.update to create it", err))
+		return
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal(fmt.Sprintf("trace doesn't match %s; run with -chantest.update to update it\n%s", path, diff(string(want), string(got))))
+	}
+}
+
+// Marshal formats tr with one event per line and no sequence numbers, so that
+// traces can be compared textually.
+func Marshal(tr chantest.Trace, opts ...Option) []byte {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var b bytes.Buffer
+	for _, e := range tr {
+		if o.round > 0 {
+			offset := e.Time.Sub(tr[0].Time).Round(o.round)
+			fmt.Fprintf(&b, "+%v ", offset)
+		}
+		if e.Kind == chantest.EventClose {
+			fmt.Fprintf(&b, "%s close\n", e.Channel)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s %#v\n", e.Channel, e.Kind, e.Value)
+	}
+	return b.Bytes()
+}
+
+// diff returns the lines of want and got, marking those that differ.
+func diff(want, got string) string {
+	wantLines := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	gotLines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	var b strings.Builder
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			fmt.Fprintf(&b, "  %s\n", w)
+			continue
+		}
+		if i < len(wantLines) {
+			fmt.Fprintf(&b, "- %s\n", w)
+		}
+		if i < len(gotLines) {
+			fmt.Fprintf(&b, "+ %s\n", g)
+		}
+	}
+	return b.String()
+}
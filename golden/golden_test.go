@@ -0,0 +1,78 @@
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/canastic/chantest"
+)
+
+// Tests importing the package can still define their own -update flag.
+var _ = flag.Bool("update", false, "update this package's own golden files")
+
+func TestAssertTrace(t *testing.T) {
+	start := time.Unix(0, 0)
+	tr := chantest.Trace{
+		{Seq: 0, Channel: "orders", Kind: chantest.EventSend, Value: "pizza", Time: start},
+		{Seq: 1, Channel: "orders", Kind: chantest.EventRecv, Value: "pizza", Time: start.Add(9 * time.Millisecond)},
+		{Seq: 2, Channel: "orders", Kind: chantest.EventClose, Time: start.Add(21 * time.Millisecond)},
+	}
+
+	AssertTrace(t, tr, "testdata/orders.golden")
+	AssertTrace(t, tr, "testdata/orders_times.golden", WithTimes(10*time.Millisecond))
+
+	tr[0].Value = "burger"
+	msg, failed := failure(func(t chantest.TestingT) {
+		AssertTrace(t, tr, "testdata/orders.golden")
+	})
+	if !failed || !strings.Contains(msg, "- orders send \"pizza\"\n+ orders send \"burger\"") {
+		t.Fatalf("unexpected failure: %q", msg)
+	}
+}
+
+func TestAssertTraceUpdate(t *testing.T) {
+	*update = true
+	defer func() { *update = false }()
+
+	path := filepath.Join(t.TempDir(), "new", "trace.golden")
+	tr := chantest.Trace{{Channel: "acks", Kind: chantest.EventSend, Value: 1}}
+	AssertTrace(t, tr, path)
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "acks send 1\n" {
+		t.Fatalf("unexpected golden file: %q", got)
+	}
+}
+
+type fakeT struct {
+	failed bool
+	msg    string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Fatal(args ...interface{}) {
+	t.failed = true
+	t.msg = fmt.Sprint(args...)
+	runtime.Goexit()
+}
+
+func failure(f func(t chantest.TestingT)) (string, bool) {
+	ft := &fakeT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(ft)
+	}()
+	<-done
+	return ft.msg, ft.failed
+}
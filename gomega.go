@@ -0,0 +1,93 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// A Matcher is a matcher in the style of Gomega. Gomega's matchers implement
+// it, and Receive returns one for Gomega to use, without either package
+// depending on the other.
+type Matcher interface {
+	Match(actual interface{}) (success bool, err error)
+	FailureMessage(actual interface{}) (message string)
+	NegatedFailureMessage(actual interface{}) (message string)
+}
+
+// AssertRecvMatch asserts that a value is quickly received from ch, and that
+// m matches it, and returns it.
+func AssertRecvMatch[T any](t TestingT, ch <-chan T, m Matcher) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	v, ok := recv(a, ch)
+	if !ok {
+		a.t.Fatal("timeout waiting for channel send or receive")
+		return v
+	}
+	if ok, err := m.Match(v); err != nil {
+		a.t.Fatal(fmt.Sprintf("matching %#v: %v", v, err))
+	} else if !ok {
+		a.t.Fatal(m.FailureMessage(v))
+	}
+	return v
+}
+
+// Receive returns a Matcher that succeeds if a value is received, within d,
+// from the channel it's matched against, and, if given, matches each of
+// matchers.
+//
+//	Expect(ch).To(chantest.Receive(50*time.Millisecond, Equal("done")))
+func Receive(d time.Duration, matchers ...Matcher) Matcher {
+	return &receiveMatcher{d: d, matchers: matchers}
+}
+
+type receiveMatcher struct {
+	d        time.Duration
+	matchers []Matcher
+
+	// The outcome of the last Match.
+	received bool
+	v        interface{}
+	failed   Matcher
+}
+
+func (m *receiveMatcher) Match(actual interface{}) (bool, error) {
+	ch := reflect.ValueOf(actual)
+	if ch.Kind() != reflect.Chan || ch.Type().ChanDir()&reflect.RecvDir == 0 {
+		return false, fmt.Errorf("Receive matcher expects a channel it can receive from, got %T", actual)
+	}
+	timeout, stop := startTimer(systemClock{}, m.d)
+	defer stop()
+	chosen, v, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)},
+	})
+	m.received, m.v, m.failed = chosen == 0 && ok, nil, nil
+	if !m.received {
+		return false, nil
+	}
+	m.v = v.Interface()
+	for _, inner := range m.matchers {
+		ok, err := inner.Match(m.v)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			m.failed = inner
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (m *receiveMatcher) FailureMessage(actual interface{}) string {
+	if m.failed != nil {
+		return fmt.Sprintf("received %#v, which doesn't match: %s", m.v, m.failed.FailureMessage(m.v))
+	}
+	return fmt.Sprintf("expected %T to receive a value within %v", actual, m.d)
+}
+
+func (m *receiveMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected %T not to receive a matching value within %v; received %#v", actual, m.d, m.v)
+}
@@ -0,0 +1,63 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// equalMatcher is like Gomega's Equal.
+type equalMatcher struct {
+	want interface{}
+}
+
+func (m equalMatcher) Match(actual interface{}) (bool, error) {
+	return reflect.DeepEqual(actual, m.want), nil
+}
+
+func (m equalMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected %#v to equal %#v", actual, m.want)
+}
+
+func (m equalMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected %#v not to equal %#v", actual, m.want)
+}
+
+func TestAssertRecvMatch(t *testing.T) {
+	ch := make(chan string, 1)
+	ch <- "done"
+	AssertRecvMatch(t, ch, equalMatcher{"done"})
+	ch <- "failed"
+	assertFails(t, `expected "failed" to equal "done"`, func(t TestingT) {
+		AssertRecvMatch(t, ch, equalMatcher{"done"})
+	})
+}
+
+func TestReceive(t *testing.T) {
+	ch := make(chan string, 1)
+	m := Receive(10*time.Millisecond, equalMatcher{"done"})
+
+	if ok, err := m.Match(ch); ok || err != nil {
+		t.Fatalf("matched empty channel: %v", err)
+	}
+	if got, want := m.FailureMessage(ch), "expected chan string to receive a value within 10ms"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	ch <- "failed"
+	if ok, _ := m.Match(ch); ok {
+		t.Fatal("matched wrong value")
+	}
+	if got, want := m.FailureMessage(ch), `received "failed", which doesn't match: expected "failed" to equal "done"`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	ch <- "done"
+	if ok, err := m.Match(ch); !ok || err != nil {
+		t.Fatalf("didn't match: %v", err)
+	}
+	if _, err := m.Match(make(chan<- string)); err == nil {
+		t.Fatal("matched send-only channel")
+	}
+}
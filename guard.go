@@ -0,0 +1,116 @@
+package chantest
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// A Guarded channel is sent to and closed through its Send and Close methods,
+// which, rather than panicking when the channel is closed twice or sent to
+// after being closed, record the stacks involved for AssertNoDoubleClose and
+// AssertNoSendAfterClose to report.
+type Guarded[T any] struct {
+	ch chan T
+	// closing is closed as Close starts, to release blocked senders.
+	closing chan struct{}
+	senders sync.WaitGroup
+
+	mu sync.Mutex
+	// closedBy is the stack of the first closer, if closed.
+	closedBy []byte
+	// doubleCloses and lateSends describe each misuse.
+	doubleCloses []string
+	lateSends    []string
+}
+
+// GuardClose returns a Guarded channel wrapping ch, which must then only be
+// sent to and closed through it.
+func GuardClose[T any](ch chan T) *Guarded[T] {
+	return &Guarded[T]{ch: ch, closing: make(chan struct{})}
+}
+
+// Chan returns the wrapped channel.
+func (g *Guarded[T]) Chan() chan T {
+	return g.ch
+}
+
+// Send sends v to the channel, or, if it's closed, or gets closed while Send
+// is blocked, records the attempt.
+func (g *Guarded[T]) Send(v T) {
+	g.mu.Lock()
+	if g.closedBy != nil {
+		g.lateSend(v)
+		g.mu.Unlock()
+		return
+	}
+	g.senders.Add(1)
+	g.mu.Unlock()
+	defer g.senders.Done()
+
+	select {
+	case g.ch <- v:
+	case <-g.closing:
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.lateSend(v)
+	}
+}
+
+// lateSend records a send of v after close, with g.mu held.
+func (g *Guarded[T]) lateSend(v T) {
+	g.lateSends = append(g.lateSends, fmt.Sprintf("send of %#v after close by:\n%s", v, debug.Stack()))
+}
+
+// Close closes the channel, or, if it's already closed, records the attempt.
+// Senders blocked in Send are released first, like they'd panic on a plain
+// channel.
+func (g *Guarded[T]) Close() {
+	stack := debug.Stack()
+	g.mu.Lock()
+	if g.closedBy != nil {
+		g.doubleCloses = append(g.doubleCloses, fmt.Sprintf("closed again by:\n%s", stack))
+		g.mu.Unlock()
+		return
+	}
+	g.closedBy = stack
+	close(g.closing)
+	g.mu.Unlock()
+
+	g.senders.Wait()
+	close(g.ch)
+}
+
+// AssertNoDoubleClose asserts that the channel hasn't been closed more than
+// once. The failure has the stacks of the first closer and the later ones.
+func (g *Guarded[T]) AssertNoDoubleClose(t TestingT, msgAndArgs ...interface{}) {
+	t = unwrap(t)
+	t.Helper()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.doubleCloses) > 0 {
+		t.Fatal(defaultOrCustomMessage(g.misuse("closed %d times", len(g.doubleCloses)+1, g.doubleCloses), msgAndArgs...))
+	}
+}
+
+// AssertNoSendAfterClose asserts that nothing has been sent to the channel
+// after it was closed. The failure has the values and the stacks of the
+// senders and of the closer.
+func (g *Guarded[T]) AssertNoSendAfterClose(t TestingT, msgAndArgs ...interface{}) {
+	t = unwrap(t)
+	t.Helper()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.lateSends) > 0 {
+		t.Fatal(defaultOrCustomMessage(g.misuse("sent to %d times after close", len(g.lateSends), g.lateSends), msgAndArgs...))
+	}
+}
+
+// misuse formats a failure for misuses, with g.mu held.
+func (g *Guarded[T]) misuse(format string, n int, misuses []string) string {
+	msg := fmt.Sprintf("channel "+format+"; first closed by:\n%s", n, g.closedBy)
+	for _, m := range misuses {
+		msg += "\n" + m
+	}
+	return msg
+}
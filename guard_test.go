@@ -0,0 +1,73 @@
+package chantest
+
+import (
+	"strings"
+	"testing"
+)
+
+func closeFirst(g *Guarded[int])  { g.Close() }
+func closeSecond(g *Guarded[int]) { g.Close() }
+
+func TestGuardClose(t *testing.T) {
+	g := GuardClose(make(chan int))
+	closeFirst(g)
+	g.AssertNoDoubleClose(t)
+	if _, ok := <-g.Chan(); ok {
+		t.Fatal("channel not closed")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		closeSecond(g)
+	}()
+	<-done
+	msg, failed := failure(func(t TestingT) {
+		g.AssertNoDoubleClose(t)
+	})
+	if !failed {
+		t.Fatal("double close not reported")
+	}
+	first := strings.Index(msg, "chantest.closeFirst")
+	again := strings.Index(msg, "closed again by:")
+	second := strings.Index(msg, "chantest.closeSecond")
+	if !strings.HasPrefix(msg, "channel closed 2 times; first closed by:") || first < 0 || first > again || again > second {
+		t.Fatalf("unexpected failure:\n%s", msg)
+	}
+}
+
+func sendLate(g *Guarded[int], v int) { g.Send(v) }
+
+func TestGuardedSend(t *testing.T) {
+	g := GuardClose(make(chan int))
+	go g.Send(1)
+	if got := Recv(t, g.Chan()); got != 1 {
+		t.Fatalf("got %d, want 1", got)
+	}
+
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		sendLate(g, 2)
+	}()
+	AssertBlockedOn(t, OpSelect, (*Guarded[int]).Send)
+	closeFirst(g)
+	<-blocked
+	sendLate(g, 3)
+	g.AssertNoDoubleClose(t)
+
+	msg, failed := failure(func(t TestingT) {
+		g.AssertNoSendAfterClose(t)
+	})
+	for _, want := range []string{
+		"channel sent to 2 times after close; first closed by:",
+		"chantest.closeFirst",
+		"send of 2 after close by:",
+		"send of 3 after close by:",
+		"chantest.sendLate",
+	} {
+		if !failed || !strings.Contains(msg, want) {
+			t.Fatalf("failure doesn't contain %q:\n%s", want, msg)
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package chantest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An Instrumented channel counts the values sent and received through it.
+//
+// Producers send to In and consumers receive from Out. In between, goroutines
+// relay values through the instrumented channel's buffer, so In and Out behave
+// like a channel with up to two more slots of buffering than it has.
+type Instrumented[T any] struct {
+	in  chan T
+	buf chan T
+	out chan T
+
+	clock Clock
+	// perturbIn and perturbOut yield as set by Perturb.
+	perturbIn, perturbOut func()
+	// rec, if set, records events under name.
+	rec  *Recorder
+	name string
+
+	mu     sync.Mutex
+	sends  int
+	recvs  int
+	closed bool
+	// lastAt is the time of the last event.
+	lastAt time.Time
+	// maxInFlight is the most values there have been in flight at once.
+	maxInFlight int
+	// limit, if positive, is reported to limitT as soon as it's exceeded.
+	limit  int
+	limitT TestingT
+	// sentAt holds the time of each send.
+	sentAt []time.Time
+	// received holds the values received from Out.
+	received []interface{}
+	// changed is closed and replaced whenever the counts change.
+	changed chan struct{}
+
+	// settleIn and settleOut are received from by relayIn and relayOut only
+	// while they're waiting, with every value they've relayed counted, until
+	// inDone and outDone are closed as they return.
+	settleIn, settleOut chan struct{}
+	inDone, outDone     chan struct{}
+}
+
+// Instrument returns an Instrumented channel with ch as its buffer. ch must not
+// be used directly afterwards.
+//
+// Of opts, WithClock sets the clock sends are timed with, and Perturb and
+// WithSeed set how the relaying goroutines yield. An Asserter passed as an
+// Option sets both.
+func Instrument[T any](ch chan T, opts ...Option) *Instrumented[T] {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	c := &Instrumented[T]{
+		clock:      cfg.clock,
+		perturbIn:  cfg.perturber(2),
+		perturbOut: cfg.perturber(3),
+		in:         make(chan T),
+		buf:        ch,
+		out:        make(chan T),
+		changed:    make(chan struct{}),
+		settleIn:   make(chan struct{}),
+		settleOut:  make(chan struct{}),
+		inDone:     make(chan struct{}),
+		outDone:    make(chan struct{}),
+	}
+	go c.relayIn()
+	go c.relayOut()
+	return c
+}
+
+// In returns the channel for producers to send to, and eventually close.
+func (c *Instrumented[T]) In() chan<- T {
+	return c.in
+}
+
+// Out returns the channel for consumers to receive from.
+func (c *Instrumented[T]) Out() <-chan T {
+	return c.out
+}
+
+func (c *Instrumented[T]) relayIn() {
+	defer close(c.inDone)
+	for {
+		var v T
+		select {
+		case <-c.settleIn:
+			continue
+		case w, ok := <-c.in:
+			if !ok {
+				close(c.buf)
+				return
+			}
+			v = w
+		}
+		now := c.clock.Now()
+		c.update(func() {
+			c.sends++
+			c.sentAt = append(c.sentAt, now)
+		})
+		c.record(EventSend, v, now)
+		c.perturbIn()
+		for sent := false; !sent; {
+			select {
+			case c.buf <- v:
+				sent = true
+			case <-c.settleIn:
+			}
+		}
+	}
+}
+
+func (c *Instrumented[T]) relayOut() {
+	defer close(c.outDone)
+	for {
+		var v T
+		select {
+		case <-c.settleOut:
+			continue
+		case w, ok := <-c.buf:
+			if !ok {
+				close(c.out)
+				c.update(func() { c.closed = true })
+				c.record(EventClose, nil, c.clock.Now())
+				return
+			}
+			v = w
+		}
+		c.perturbOut()
+		for sent := false; !sent; {
+			select {
+			case c.out <- v:
+				sent = true
+			case <-c.settleOut:
+			}
+		}
+		c.update(func() {
+			c.recvs++
+			c.received = append(c.received, v)
+		})
+		c.perturbOut()
+		c.record(EventRecv, v, c.clock.Now())
+	}
+}
+
+// settle waits for the relaying goroutines to count every value that has gone
+// through In or Out so far, which they do only after handing it on, so that a
+// value just received is counted by the time settle returns.
+func (c *Instrumented[T]) settle() {
+	select {
+	case c.settleIn <- struct{}{}:
+	case <-c.inDone:
+	}
+	select {
+	case c.settleOut <- struct{}{}:
+	case <-c.outDone:
+	}
+}
+
+func (c *Instrumented[T]) record(kind EventKind, v interface{}, at time.Time) {
+	if c.rec != nil {
+		c.rec.record(Event{Channel: c.name, Kind: kind, Value: v, Time: at})
+	}
+}
+
+func (c *Instrumented[T]) update(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f()
+	c.lastAt = c.clock.Now()
+	if inFlight := c.sends - c.recvs; inFlight > c.maxInFlight {
+		c.maxInFlight = inFlight
+		if c.limit > 0 && inFlight == c.limit+1 {
+			if e, ok := c.limitT.(errorer); ok {
+				e.Error(fmt.Sprintf("%d values in flight, over the limit of %d", inFlight, c.limit))
+			}
+		}
+	}
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *Instrumented[T]) activity() activity {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state := "open"
+	if c.closed {
+		state = "closed"
+	}
+	return activity{
+		last:  c.lastAt,
+		state: fmt.Sprintf("%d sent, %d received, %s", c.sends, c.recvs, state),
+	}
+}
+
+func (c *Instrumented[T]) outChan() interface{} {
+	return c.out
+}
+
+func (c *Instrumented[T]) receivedValues() []interface{} {
+	c.settle()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]interface{}(nil), c.received...)
+}
+
+func (c *Instrumented[T]) drain(a *Asserter) []interface{} {
+	var pending []interface{}
+	for n := c.Len(); len(pending) < n; {
+		v, ok := c.drainOne(a)
+		if !ok {
+			break
+		}
+		pending = append(pending, v)
+	}
+	return pending
+}
+
+func (c *Instrumented[T]) awaitClose(expired <-chan struct{}) ([]interface{}, bool) {
+	return awaitClose(c.Out(), expired)
+}
+
+func (c *Instrumented[T]) drainOne(a *Asserter) (T, bool) {
+	tm := a.startTimer()
+	defer tm.stop()
+	select {
+	case v, ok := <-c.out:
+		return v, ok
+	case <-tm.C:
+		var zero T
+		return zero, false
+	}
+}
+
+// Sends returns how many values have been sent to In.
+func (c *Instrumented[T]) Sends() int {
+	c.settle()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sends
+}
+
+// Recvs returns how many values have been received from Out.
+func (c *Instrumented[T]) Recvs() int {
+	c.settle()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recvs
+}
+
+// Len returns how many values have been sent but not yet received.
+func (c *Instrumented[T]) Len() int {
+	c.settle()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sends - c.recvs
+}
+
+// Closed reports whether In has been closed and, after that, every value
+// received, so that Out is closed too.
+func (c *Instrumented[T]) Closed() bool {
+	c.settle()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// AssertSendCount asserts that the number of values sent very quickly is, or
+// gets to, n.
+func (c *Instrumented[T]) AssertSendCount(t TestingT, n int, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	c.assertEventually(a, func() (bool, string) {
+		return c.sends == n, fmt.Sprintf("send count is %d, want %d", c.sends, n)
+	}, msgAndArgs...)
+}
+
+// AssertRecvCount asserts that the number of values received very quickly is,
+// or gets to, n.
+func (c *Instrumented[T]) AssertRecvCount(t TestingT, n int, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	c.assertEventually(a, func() (bool, string) {
+		return c.recvs == n, fmt.Sprintf("receive count is %d, want %d", c.recvs, n)
+	}, msgAndArgs...)
+}
+
+// AssertLen asserts that the number of values sent but not yet received very
+// quickly is, or gets to, n.
+func (c *Instrumented[T]) AssertLen(t TestingT, n int, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	c.assertEventually(a, func() (bool, string) {
+		return c.sends-c.recvs == n, fmt.Sprintf("%d values in flight, want %d", c.sends-c.recvs, n)
+	}, msgAndArgs...)
+}
+
+// AssertClosed asserts that the channel very quickly is, or gets, closed.
+func (c *Instrumented[T]) AssertClosed(t TestingT, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	c.assertEventually(a, func() (bool, string) {
+		return c.closed, fmt.Sprintf("channel not closed; %d sent, %d received", c.sends, c.recvs)
+	}, msgAndArgs...)
+}
+
+// assertEventually fails unless check, called with c.mu held, passes before
+// the Asserter's timeout. check also returns the failure message.
+func (c *Instrumented[T]) assertEventually(a *Asserter, check func() (bool, string), msgAndArgs ...interface{}) {
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	for {
+		c.mu.Lock()
+		ok, msg := check()
+		changed := c.changed
+		c.mu.Unlock()
+		if ok {
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+			return
+		}
+	}
+}
+
+// Limit makes the channel report a failure as soon as it has more than n
+// values in flight, if t has an Error method, which, unlike Fatal, can be
+// called from any goroutine, like *testing.T's. AssertMaxInFlight reports it
+// after the fact either way.
+func (c *Instrumented[T]) Limit(t TestingT, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limit, c.limitT = n, unwrap(t)
+}
+
+// MaxInFlight returns the most values there have been in flight at once,
+// that is, sent but not yet received.
+func (c *Instrumented[T]) MaxInFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxInFlight
+}
+
+// AssertMaxInFlight asserts that there have never been more than n values in
+// flight at once.
+func (c *Instrumented[T]) AssertMaxInFlight(t TestingT, n int, msgAndArgs ...interface{}) {
+	t = unwrap(t)
+	t.Helper()
+	if max := c.MaxInFlight(); max > n {
+		t.Fatal(defaultOrCustomMessage(fmt.Sprintf("%d values in flight at once, want at most %d", max, n), msgAndArgs...))
+	}
+}
+
+// InterArrival summarizes the times between consecutive sends to an
+// Instrumented channel.
+type InterArrival struct {
+	// N is the number of gaps, one less than the number of sends.
+	N              int
+	Min, Max, Mean time.Duration
+
+	sorted []time.Duration
+}
+
+// Percentile returns the gap that p percent of gaps are shorter than or equal
+// to, with p between 0 and 100. It returns 0 if there are no gaps.
+func (s InterArrival) Percentile(p float64) time.Duration {
+	if len(s.sorted) == 0 {
+		return 0
+	}
+	i := int(p/100*float64(len(s.sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(s.sorted) {
+		i = len(s.sorted) - 1
+	}
+	return s.sorted[i]
+}
+
+// Gaps returns the times between consecutive sends.
+func (c *Instrumented[T]) Gaps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return gaps(c.sentAt)
+}
+
+// InterArrival returns statistics of the times between consecutive sends.
+func (c *Instrumented[T]) InterArrival() InterArrival {
+	gaps := c.Gaps()
+	s := InterArrival{N: len(gaps), sorted: gaps}
+	if len(gaps) == 0 {
+		return s
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	var sum time.Duration
+	for _, g := range gaps {
+		sum += g
+	}
+	s.Min, s.Max, s.Mean = gaps[0], gaps[len(gaps)-1], sum/time.Duration(len(gaps))
+	return s
+}
+
+// AssertMaxGap asserts that no two consecutive sends were more than d apart.
+//
+// Unless the channel is closed, the time since the last send counts as a gap
+// too, so that a producer that stopped sending is caught.
+func (c *Instrumented[T]) AssertMaxGap(t TestingT, d time.Duration, msgAndArgs ...interface{}) {
+	t = unwrap(t)
+	t.Helper()
+	c.mu.Lock()
+	gaps := gaps(c.sentAt)
+	if !c.closed && len(c.sentAt) > 0 {
+		gaps = append(gaps, c.clock.Now().Sub(c.sentAt[len(c.sentAt)-1]))
+	}
+	c.mu.Unlock()
+
+	var long []string
+	for i, g := range gaps {
+		if g > d {
+			long = append(long, fmt.Sprintf("#%d: %v", i, g))
+		}
+	}
+	if len(long) > 0 {
+		t.Fatal(defaultOrCustomMessage(fmt.Sprintf("gaps between sends longer than %v: %s", d, strings.Join(long, ", ")), msgAndArgs...))
+	}
+}
+
+// AssertMinGap asserts that no two consecutive sends were less than d apart.
+func (c *Instrumented[T]) AssertMinGap(t TestingT, d time.Duration, msgAndArgs ...interface{}) {
+	t = unwrap(t)
+	t.Helper()
+	var short []string
+	for i, g := range c.Gaps() {
+		if g < d {
+			short = append(short, fmt.Sprintf("#%d: %v", i, g))
+		}
+	}
+	if len(short) > 0 {
+		t.Fatal(defaultOrCustomMessage(fmt.Sprintf("gaps between sends shorter than %v: %s", d, strings.Join(short, ", ")), msgAndArgs...))
+	}
+}
+
+func gaps(times []time.Time) []time.Duration {
+	var gaps []time.Duration
+	for i := 1; i < len(times); i++ {
+		gaps = append(gaps, times[i].Sub(times[i-1]))
+	}
+	return gaps
+}
@@ -0,0 +1,117 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInstrument(t *testing.T) {
+	c := Instrument(make(chan int, 2))
+
+	assertPasses(t, func(t TestingT) {
+		Send(t, c.In(), 1)
+		Send(t, c.In(), 2)
+		c.AssertSendCount(t, 2)
+		c.AssertLen(t, 2)
+		if got := Recv(t, c.Out()); got != 1 {
+			t.Fatal("unexpected value", got)
+		}
+		c.AssertRecvCount(t, 1)
+		c.AssertLen(t, 1)
+		close(c.In())
+		Recv(t, c.Out())
+		c.AssertClosed(t)
+	})
+	if c.Sends() != 2 || c.Recvs() != 2 || c.Len() != 0 || !c.Closed() {
+		t.Fatalf("unexpected counts: %d sends, %d receives, %d in flight, closed %v", c.Sends(), c.Recvs(), c.Len(), c.Closed())
+	}
+
+	assertFails(t, "send count is 2, want 3", func(t TestingT) {
+		c.AssertSendCount(New(t, short), 3)
+	})
+	assertFails(t, "channel not closed; 1 sent, 0 received", func(t TestingT) {
+		c := Instrument(make(chan int, 1))
+		c.In() <- 1
+		c.AssertClosed(New(t, short))
+	})
+}
+
+func TestInstrumentInterArrival(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	c := Instrument(make(chan int, 10), WithClock(clock))
+	for i, gap := range []time.Duration{0, 10, 30, 20} {
+		clock.Advance(gap * time.Millisecond)
+		c.In() <- i
+		c.AssertSendCount(t, i+1)
+	}
+
+	s := c.InterArrival()
+	if s.N != 3 || s.Min != 10*time.Millisecond || s.Max != 30*time.Millisecond || s.Mean != 20*time.Millisecond {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+	if p := s.Percentile(50); p != 20*time.Millisecond {
+		t.Fatalf("unexpected median %v", p)
+	}
+
+	assertPasses(t, func(t TestingT) {
+		c.AssertMaxGap(t, 30*time.Millisecond)
+		c.AssertMinGap(t, 10*time.Millisecond)
+	})
+	assertFails(t, "gaps between sends longer than 20ms: #1: 30ms", func(t TestingT) {
+		c.AssertMaxGap(t, 20*time.Millisecond)
+	})
+	assertFails(t, "gaps between sends shorter than 20ms: #0: 10ms", func(t TestingT) {
+		c.AssertMinGap(t, 20*time.Millisecond)
+	})
+
+	clock.Advance(time.Second)
+	assertFails(t, "#3: 1s", func(t TestingT) {
+		c.AssertMaxGap(t, 30*time.Millisecond)
+	})
+}
+
+func TestInstrumentMaxInFlight(t *testing.T) {
+	c := Instrument(make(chan int, 5))
+	et := &errorT{}
+	c.Limit(et, 2)
+	for i := 0; i < 3; i++ {
+		c.In() <- i
+	}
+	c.AssertSendCount(t, 3)
+	<-c.Out()
+	c.AssertRecvCount(t, 1)
+	c.In() <- 3
+	c.AssertSendCount(t, 4)
+
+	if got := c.MaxInFlight(); got != 3 {
+		t.Fatalf("max in flight %d, want 3", got)
+	}
+	c.AssertMaxInFlight(t, 3)
+	assertFails(t, "3 values in flight at once, want at most 2", func(t TestingT) {
+		c.AssertMaxInFlight(t, 2)
+	})
+	et.mu.Lock()
+	defer et.mu.Unlock()
+	if want := []string{"3 values in flight, over the limit of 2"}; len(et.errors) != 1 || et.errors[0] != want[0] {
+		t.Fatalf("got errors %q, want %q", et.errors, want)
+	}
+}
+
+func TestInstrumentCountsSettled(t *testing.T) {
+	c := Instrument(make(chan int, 1))
+	for i := 1; i <= 100; i++ {
+		c.In() <- i
+		if c.Sends() != i {
+			t.Fatalf("send #%d: %d sends counted", i, c.Sends())
+		}
+		<-c.Out()
+		if c.Recvs() != i || c.Len() != 0 {
+			t.Fatalf("receive #%d: %d receives counted, %d in flight", i, c.Recvs(), c.Len())
+		}
+	}
+	close(c.In())
+	<-c.Out()
+	if !c.Closed() {
+		t.Fatal("closed channel not counted as closed")
+	}
+}
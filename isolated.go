@@ -0,0 +1,27 @@
+//go:build go1.25
+// +build go1.25
+
+package chantest
+
+import (
+	"testing"
+	"testing/synctest"
+)
+
+// RunIsolated runs body inside a testing/synctest bubble, with an Asserter for
+// body's t.
+//
+// In the bubble, time is virtual: it only advances when every goroutine in the
+// bubble is durably blocked. Assertions that wait for their Before duration,
+// like AssertNoRecv, then finish instantly instead of burning wall time. The
+// code under test must run in goroutines started from body, and must not
+// block on I/O or other goroutines outside the bubble.
+//
+// With Go versions older than 1.25, where testing/synctest isn't available,
+// RunIsolated runs body directly with t.
+func RunIsolated(t *testing.T, body func(t *testing.T, a *Asserter)) {
+	t.Helper()
+	synctest.Test(t, func(t *testing.T) {
+		body(t, New(t))
+	})
+}
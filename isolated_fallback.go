@@ -0,0 +1,15 @@
+//go:build !go1.25
+// +build !go1.25
+
+package chantest
+
+import "testing"
+
+// RunIsolated runs body with an Asserter for t.
+//
+// With Go 1.25 and later, body runs inside a testing/synctest bubble, where
+// time is virtual; see its documentation in that version.
+func RunIsolated(t *testing.T, body func(t *testing.T, a *Asserter)) {
+	t.Helper()
+	body(t, New(t))
+}
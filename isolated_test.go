@@ -0,0 +1,23 @@
+//go:build go1.25
+// +build go1.25
+
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunIsolated(t *testing.T) {
+	start := time.Now()
+	RunIsolated(t, func(t *testing.T, a *Asserter) {
+		a = New(a, Before(time.Hour))
+		ch := make(chan int)
+		a.AssertNoRecv(ch)
+		go func() { ch <- 1 }()
+		a.AssertRecv(ch)
+	})
+	if elapsed := time.Since(start); elapsed > time.Minute {
+		t.Fatalf("isolated assertions took %v of wall time", elapsed)
+	}
+}
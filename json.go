@@ -0,0 +1,35 @@
+package chantest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AssertRecvJSONEq asserts that a JSON document is quickly received from ch,
+// and that it's structurally equal to wantJSON, that is, regardless of
+// formatting and of the order of object keys. It returns the received
+// document. The failure lists the differing values by path.
+func AssertRecvJSONEq[T ~[]byte](t TestingT, ch <-chan T, wantJSON string, msgAndArgs ...interface{}) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	var want interface{}
+	if err := json.Unmarshal([]byte(wantJSON), &want); err != nil {
+		a.t.Fatal(fmt.Sprintf("invalid expected JSON %s: %v", wantJSON, err))
+		return nil
+	}
+	doc, ok := recv(a, ch)
+	if !ok {
+		a.t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...))
+		return doc
+	}
+	var got interface{}
+	if err := json.Unmarshal(doc, &got); err != nil {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("received invalid JSON %s: %v", doc, err), msgAndArgs...))
+		return doc
+	}
+	if diffs := newEquality(nil).diff(got, want); len(diffs) > 0 {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("received %s, want %s; differences:\n\t%s", doc, wantJSON, strings.Join(diffs, "\n\t")), msgAndArgs...))
+	}
+	return doc
+}
@@ -0,0 +1,30 @@
+package chantest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAssertRecvJSONEq(t *testing.T) {
+	ch := make(chan []byte, 1)
+	ch <- []byte(`{"id": 1, "tags": ["a", "b"]}`)
+	assertPasses(t, func(t TestingT) {
+		AssertRecvJSONEq(t, ch, `{"tags":["a","b"],"id":1}`)
+	})
+
+	raw := make(chan json.RawMessage, 1)
+	raw <- json.RawMessage(`{"id": 1, "tags": ["a", "c"]}`)
+	assertFails(t, "differences:\n\t[\"tags\"][1]: got \"c\", want \"b\"", func(t TestingT) {
+		AssertRecvJSONEq(t, raw, `{"id": 1, "tags": ["a", "b"]}`)
+	})
+	raw <- json.RawMessage(`{`)
+	assertFails(t, "received invalid JSON {: ", func(t TestingT) {
+		AssertRecvJSONEq(t, raw, `{}`)
+	})
+	assertFails(t, "invalid expected JSON [: ", func(t TestingT) {
+		AssertRecvJSONEq(t, raw, `[`)
+	})
+	assertFails(t, "no document", func(t TestingT) {
+		AssertRecvJSONEq(New(t, short), raw, `{}`, "no document")
+	})
+}
@@ -0,0 +1,35 @@
+package chantest
+
+import (
+	"fmt"
+	"time"
+)
+
+// MeasureRecv is like Recv, but also returns how long it took for the value
+// to arrive, as measured by the Asserter's Clock if t is one.
+func MeasureRecv[T any](t TestingT, ch <-chan T, msgAndArgs ...interface{}) (T, time.Duration) {
+	a := asserterFor(t)
+	a.t.Helper()
+	start := a.clock.Now()
+	v, ok := recv(a, ch)
+	elapsed := a.clock.Now().Sub(start)
+	if !ok {
+		a.t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...))
+	}
+	return v, elapsed
+}
+
+// AssertRecvWithinLatency is like Recv, but also fails if the value takes
+// longer than max to arrive, even if it's before the timeout.
+//
+// Useful for code that batches or delays values internally, and should still
+// deliver them promptly.
+func AssertRecvWithinLatency[T any](t TestingT, ch <-chan T, max time.Duration, msgAndArgs ...interface{}) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	v, elapsed := MeasureRecv(a, ch, msgAndArgs...)
+	if elapsed > max {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("value received after %v, want within %v", elapsed, max), msgAndArgs...))
+	}
+	return v
+}
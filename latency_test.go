@@ -0,0 +1,36 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeasureRecv(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	a := New(t, WithClock(clock), Before(time.Hour))
+	ch := make(chan int)
+	go func() {
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+		ch <- 1
+	}()
+	v, elapsed := MeasureRecv(a, ch)
+	if v != 1 || elapsed != time.Second {
+		t.Fatalf("got %v after %v", v, elapsed)
+	}
+}
+
+func TestAssertRecvWithinLatency(t *testing.T) {
+	ch := make(chan int, 1)
+	assertPasses(t, func(t TestingT) {
+		ch <- 1
+		AssertRecvWithinLatency(t, ch, time.Second)
+	})
+	assertFails(t, "want within 1ms", func(t TestingT) {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			ch <- 1
+		}()
+		AssertRecvWithinLatency(t, ch, time.Millisecond)
+	})
+}
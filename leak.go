@@ -0,0 +1,74 @@
+package chantest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AssertAllClosed asserts that every registered channel is, or before the
+// Asserter's timeout gets, closed, with no values left in it. Values still in
+// a channel are received, and counted in the failure as stranded, along with
+// the channels left open.
+//
+// Registered with Cleanup right after the channels, it checks that the test's
+// teardown closes them:
+//
+//	a := chantest.New(t)
+//	events := chantest.Make[Event](a, 10)
+//	t.Cleanup(a.AssertAllClosed)
+func (a *Asserter) AssertAllClosed(msgAndArgs ...interface{}) {
+	a.t.Helper()
+	// Every channel left open waits for the timeout, so it's turned into a
+	// channel that stays closed once it fires.
+	timeout, stop := a.timeout()
+	defer stop()
+	expired, finished := make(chan struct{}), make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-timeout:
+			close(expired)
+		case <-finished:
+		}
+	}()
+	names, channels := a.registry.channelList()
+	var leaks []string
+	for i, c := range channels {
+		stranded, closed := c.awaitClose(expired)
+		var problems []string
+		if !closed {
+			problems = append(problems, "left open")
+		}
+		if len(stranded) > 0 {
+			problems = append(problems, fmt.Sprintf("%d values stranded: %v", len(stranded), stranded))
+		}
+		if len(problems) > 0 {
+			leaks = append(leaks, fmt.Sprintf("%s: %s", names[i], strings.Join(problems, ", ")))
+		}
+	}
+	if len(leaks) > 0 {
+		a.t.Fatal(defaultOrCustomMessage("registered channels not closed cleanly: "+strings.Join(leaks, "; "), msgAndArgs...))
+	}
+}
+
+// awaitClose receives from ch until it's closed or expired is, and returns
+// the values received and whether ch was closed. Once expired is closed, it
+// still receives what ch yields for pollInterval, so that channels checked
+// after the deadline are reported like the ones before.
+func awaitClose[T any](ch <-chan T, expired <-chan struct{}) (stranded []interface{}, closed bool) {
+	var grace <-chan time.Time
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return stranded, true
+			}
+			stranded = append(stranded, v)
+		case <-expired:
+			expired, grace = nil, time.After(pollInterval)
+		case <-grace:
+			return stranded, false
+		}
+	}
+}
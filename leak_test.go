@@ -0,0 +1,36 @@
+package chantest
+
+import "testing"
+
+func TestAssertAllClosed(t *testing.T) {
+	a := New(t, short)
+	closed := Make[int](a, 1)
+	late := Instrument(make(chan string, 1))
+	a.Register("late", late)
+	close(closed)
+	go func() {
+		late.In() <- "bye"
+		close(late.In())
+	}()
+	AssertRecv(a, late.Out())
+	a.AssertAllClosed()
+
+	a = New(t, short)
+	open := Make[int](a, 2)
+	open <- 1
+	open <- 2
+	stranded := Instrument(make(chan string, 1))
+	a.Register("stranded", stranded)
+	stranded.In() <- "left"
+	close(stranded.In())
+	assertFails(t, `registered channels not closed cleanly: channel made at leak_test.go:19: left open, 2 values stranded: [1 2]; stranded: 1 values stranded: [left]`, func(t TestingT) {
+		New(t, a).AssertAllClosed()
+	})
+
+	a = New(t, short)
+	a.Register("first", Instrument(make(chan int)))
+	a.Register("second", Instrument(make(chan int)))
+	assertFails(t, "registered channels not closed cleanly: first: left open; second: left open", func(t TestingT) {
+		New(t, a).AssertAllClosed()
+	})
+}
@@ -0,0 +1,28 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AssertLenEventually asserts that the number of values buffered in ch, which
+// must be a channel of any direction, very quickly is, or gets to, n. The
+// failure lists the lengths observed, each time it changed.
+//
+// Useful for checking that a producer fills a buffer before any consumer
+// starts.
+func AssertLenEventually(t TestingT, ch interface{}, n int, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	v := reflect.ValueOf(ch)
+	var history []int
+	if !a.poll(func() bool {
+		l := v.Len()
+		if len(history) == 0 || history[len(history)-1] != l {
+			history = append(history, l)
+		}
+		return l == n
+	}) {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("channel length didn't get to %d; observed lengths %v", n, history), msgAndArgs...))
+	}
+}
@@ -0,0 +1,27 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssertLenEventually(t *testing.T) {
+	ch := make(chan int, 3)
+	go func() {
+		for i := 0; i < 3; i++ {
+			ch <- i
+		}
+	}()
+	AssertLenEventually(t, ch, 3)
+	<-ch
+	var send chan<- int = ch
+	AssertLenEventually(t, send, 2)
+
+	assertFails(t, "channel length didn't get to 3; observed lengths [2 1]", func(t TestingT) {
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			<-ch
+		}()
+		AssertLenEventually(New(t, short), ch, 3)
+	})
+}
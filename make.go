@@ -0,0 +1,125 @@
+package chantest
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+// Make returns a new channel with the given capacity that, at the end of the
+// test, fails it if values are left in the channel's buffer, which otherwise
+// goes unnoticed when a producer sends more than the test consumes.
+//
+// The check is registered with t's Cleanup method, if it has one, like
+// *testing.T. If t is an Asserter, the channel is also registered with it,
+// under a name with the location of the call, like with Register, except
+// that, since the channel isn't instrumented, Verify can only report the
+// values left in it.
+func Make[T any](t TestingT, capacity int) chan T {
+	a := asserterFor(t)
+	a.t.Helper()
+	return makeChecked[T](t, a, capacity, anyState)
+}
+
+// MakeClosed is like Make, but the check also fails the test if the channel
+// hasn't been closed by then, as when a producer never finishes.
+func MakeClosed[T any](t TestingT, capacity int) chan T {
+	a := asserterFor(t)
+	a.t.Helper()
+	return makeChecked[T](t, a, capacity, closedState)
+}
+
+// MakeOpen is like Make, but the check also fails the test if the channel has
+// been closed by then.
+func MakeOpen[T any](t TestingT, capacity int) chan T {
+	a := asserterFor(t)
+	a.t.Helper()
+	return makeChecked[T](t, a, capacity, openState)
+}
+
+// A chanState is the state a channel from Make is expected to end the test
+// in.
+type chanState int
+
+const (
+	anyState chanState = iota
+	openState
+	closedState
+)
+
+// makeChecked implements Make, MakeClosed and MakeOpen, which must call it
+// directly, for the location in the channel's name.
+func makeChecked[T any](t TestingT, a *Asserter, capacity int, want chanState) chan T {
+	a.t.Helper()
+	m := made[T]{make(chan T, capacity)}
+	name := "channel"
+	if _, file, line, ok := runtime.Caller(2); ok {
+		name = fmt.Sprintf("channel made at %s:%d", filepath.Base(file), line)
+	}
+	if _, ok := t.(*Asserter); ok {
+		a.Register(name, m)
+	}
+	if c, ok := a.t.(cleanuper); ok {
+		c.Cleanup(func() {
+			a.t.Helper()
+			left := m.drain(a)
+			closed := false
+			select {
+			case v, ok := <-m.ch:
+				if ok {
+					left = append(left, v)
+				}
+				closed = !ok
+			default:
+			}
+			switch {
+			case len(left) > 0:
+				a.t.Fatal(fmt.Sprintf("%s: %d values left at the end of the test: %v", name, len(left), left))
+			case want == closedState && !closed:
+				a.t.Fatal(fmt.Sprintf("%s: not closed at the end of the test", name))
+			case want == openState && closed:
+				a.t.Fatal(fmt.Sprintf("%s: closed at the end of the test", name))
+			}
+		})
+	}
+	return m.ch
+}
+
+// made is the Observable of a channel from Make.
+type made[T any] struct {
+	ch chan T
+}
+
+func (m made[T]) activity() activity {
+	return activity{state: fmt.Sprintf("%d buffered", len(m.ch))}
+}
+
+func (m made[T]) outChan() interface{} {
+	return m.ch
+}
+
+// receivedValues are unknown, so they're taken to be what assertions matched.
+func (m made[T]) receivedValues() []interface{} {
+	return nil
+}
+
+// drain receives the values in the buffer, without waiting for more.
+func (m made[T]) drain(*Asserter) []interface{} {
+	var left []interface{}
+	for n := len(m.ch); len(left) < n; {
+		select {
+		case v, ok := <-m.ch:
+			if !ok {
+				return left
+			}
+			left = append(left, v)
+		default:
+			return left
+		}
+	}
+	return left
+}
+
+func (m made[T]) awaitClose(expired <-chan struct{}) ([]interface{}, bool) {
+	return awaitClose(m.ch, expired)
+}
@@ -0,0 +1,64 @@
+package chantest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	ch := Make[int](t, 1)
+	ch <- 1
+	AssertRecv(t, ch)
+
+	ct := &cleanupT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ch := Make[int](ct, 3)
+		ch <- 1
+		ch <- 2
+		close(ch)
+		ct.cleanups[0]()
+	}()
+	<-done
+	if want := "make_test.go:17: 2 values left at the end of the test: [1 2]"; !ct.failed || !strings.Contains(ct.msg, want) {
+		t.Fatalf("got failure %q, want %q", ct.msg, want)
+	}
+
+	a := New(t, Strict())
+	ch = Make[int](a, 1)
+	ch <- 1
+	assertFails(t, "channel made at make_test.go:29: never received [1]", func(t TestingT) {
+		New(t, a).Verify()
+	})
+}
+
+func TestMakeState(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		make  func(TestingT, int) chan int
+		close bool
+		want  string
+	}{
+		{"closed", MakeClosed[int], true, ""},
+		{"not closed", MakeClosed[int], false, "make_test.go:53: not closed at the end of the test"},
+		{"open", MakeOpen[int], false, ""},
+		{"not open", MakeOpen[int], true, "make_test.go:53: closed at the end of the test"},
+		{"any", Make[int], true, ""},
+	} {
+		ct := &cleanupT{}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			ch := c.make(ct, 1)
+			if c.close {
+				close(ch)
+			}
+			ct.cleanups[0]()
+		}()
+		<-done
+		if c.want == "" && ct.failed || c.want != "" && !strings.Contains(ct.msg, c.want) {
+			t.Errorf("%s: got failure %q, want %q", c.name, ct.msg, c.want)
+		}
+	}
+}
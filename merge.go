@@ -0,0 +1,36 @@
+package chantest
+
+import "sync"
+
+// An Indexed value is a value received from one of the channels passed to
+// Merge, with the position of that channel.
+type Indexed[T any] struct {
+	Index int
+	Value T
+}
+
+// Merge returns a channel that receives the values received from each of chs,
+// along with the channel's index, and which is closed once all of chs are.
+// Assertions on it wait as usual, for whichever channel sends first.
+//
+// Each of chs has a value received ahead of the merged channel's consumer, so
+// producers see them received a little earlier than they are.
+func Merge[T any](chs ...<-chan T) <-chan Indexed[T] {
+	merged := make(chan Indexed[T])
+	var wg sync.WaitGroup
+	wg.Add(len(chs))
+	for i, ch := range chs {
+		i, ch := i, ch
+		go func() {
+			defer wg.Done()
+			for v := range ch {
+				merged <- Indexed[T]{Index: i, Value: v}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged
+}
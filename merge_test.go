@@ -0,0 +1,25 @@
+package chantest
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	a, b := make(chan int), make(chan int)
+	merged := Merge[int](a, b)
+
+	NoRecv(New(t, short), merged)
+	go func() { b <- 2 }()
+	if got := Recv(t, merged); got != (Indexed[int]{Index: 1, Value: 2}) {
+		t.Fatalf("got %+v", got)
+	}
+	go func() { a <- 1 }()
+	if got := Recv(t, merged); got != (Indexed[int]{Index: 0, Value: 1}) {
+		t.Fatalf("got %+v", got)
+	}
+
+	close(a)
+	NoRecv(New(t, short), merged)
+	close(b)
+	if _, ok := <-merged; ok {
+		t.Fatal("merged channel not closed")
+	}
+}
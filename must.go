@@ -0,0 +1,33 @@
+package chantest
+
+import "fmt"
+
+// MustRecv is like Recv, but panics if nothing is received within Default,
+// instead of failing a test, for TestMain, fixtures and examples, where there's
+// no test to fail.
+func MustRecv[T any](ch <-chan T) T {
+	return Recv(PanicT(), ch)
+}
+
+// MustSend is like Send, but panics if v isn't sent within Default, like
+// MustRecv.
+func MustSend[T any](ch chan<- T, v T) {
+	Send(PanicT(), ch, v)
+}
+
+// PanicT returns a TestingT whose Fatal panics with its message, so that any
+// assertion can be used where MustRecv and MustSend are, with any
+// configuration:
+//
+//	v := chantest.Recv(chantest.New(chantest.PanicT(), chantest.Before(time.Second)), ch)
+func PanicT() TestingT {
+	return panicT{}
+}
+
+type panicT struct{}
+
+func (panicT) Helper() {}
+
+func (panicT) Fatal(args ...interface{}) {
+	panic(fmt.Sprint(args...))
+}
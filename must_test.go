@@ -0,0 +1,25 @@
+package chantest
+
+import "testing"
+
+func TestMust(t *testing.T) {
+	ch := make(chan int, 1)
+	MustSend(ch, 1)
+	if got := MustRecv(ch); got != 1 {
+		t.Fatalf("received %d, want 1", got)
+	}
+
+	assertPanics := func(want string, f func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r != want {
+				t.Fatalf("recovered %v, want %q", r, want)
+			}
+		}()
+		f()
+	}
+	assertPanics("timeout waiting for channel send or receive", func() { MustRecv(ch) })
+	ch <- 2
+	assertPanics("timeout waiting for channel send or receive", func() { MustSend(ch, 3) })
+	assertPanics("no value", func() { Recv(New(PanicT(), short), make(chan int), "no value") })
+}
@@ -0,0 +1,83 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AssertRecvNil asserts that a nil value is quickly received from ch, as in
+// v == nil, so for a chan error, an error holding a nil pointer isn't nil,
+// and the failure says so. A closed channel doesn't count as a nil value.
+func AssertRecvNil[T any](t TestingT, ch <-chan T, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	v, ok, received := recvOrClose(a, ch)
+	var msg string
+	switch {
+	case !received:
+		msg = "timeout waiting for channel send or receive"
+	case !ok:
+		msg = "channel closed instead of receiving nil"
+	case !nillable[T]():
+		msg = fmt.Sprintf("received %#v, which can't be nil", v)
+	case isNil(v):
+		return
+	case holdsNil(v):
+		msg = fmt.Sprintf("received a non-nil %s holding a nil %T", reflect.TypeOf((*T)(nil)).Elem(), v)
+	default:
+		msg = fmt.Sprintf("received %#v, want nil", v)
+	}
+	a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+}
+
+// AssertRecvNotNil asserts that a value that isn't nil, as in v != nil, is
+// quickly received from ch, and returns it.
+func AssertRecvNotNil[T any](t TestingT, ch <-chan T, msgAndArgs ...interface{}) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	v, ok, received := recvOrClose(a, ch)
+	var msg string
+	switch {
+	case !received:
+		msg = "timeout waiting for channel send or receive"
+	case !ok:
+		msg = "channel closed instead of receiving a value"
+	case isNil(v):
+		msg = "received nil"
+	default:
+		return v
+	}
+	a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+	return v
+}
+
+// nillable reports whether values of T can be nil.
+func nillable[T any]() bool {
+	switch reflect.TypeOf((*T)(nil)).Elem().Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+// isNil reports whether v is nil, as in v == nil where v has its static type,
+// rather than interface{}.
+func isNil[T any](v T) bool {
+	if !nillable[T]() {
+		return false
+	}
+	return reflect.ValueOf(&v).Elem().IsNil()
+}
+
+// holdsNil reports whether v is a non-nil interface holding a nil value.
+func holdsNil[T any](v T) bool {
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() != reflect.Interface || rv.IsNil() {
+		return false
+	}
+	switch rv = rv.Elem(); rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package chantest
+
+import (
+	"errors"
+	"testing"
+)
+
+type nilErr struct{}
+
+func (*nilErr) Error() string { return "nilErr" }
+
+func TestAssertRecvNil(t *testing.T) {
+	errs := make(chan error, 1)
+	errs <- nil
+	AssertRecvNil(t, errs)
+	errs <- errors.New("failed")
+	if err := AssertRecvNotNil(t, errs); err.Error() != "failed" {
+		t.Fatalf("got %v", err)
+	}
+
+	var typed *nilErr
+	errs <- typed
+	assertFails(t, "received a non-nil error holding a nil *chantest.nilErr", func(t TestingT) {
+		AssertRecvNil(t, errs)
+	})
+	errs <- typed
+	AssertRecvNotNil(t, errs)
+	errs <- errors.New("failed")
+	assertFails(t, "received &errors.errorString{s:\"failed\"}, want nil", func(t TestingT) {
+		AssertRecvNil(t, errs)
+	})
+	errs <- nil
+	assertFails(t, "received nil", func(t TestingT) {
+		AssertRecvNotNil(t, errs)
+	})
+
+	ptrs := make(chan *int, 1)
+	ptrs <- nil
+	AssertRecvNil(t, ptrs)
+	close(ptrs)
+	assertFails(t, "channel closed instead of receiving nil", func(t TestingT) {
+		AssertRecvNil(t, ptrs)
+	})
+	assertFails(t, "channel closed instead of receiving a value", func(t TestingT) {
+		AssertRecvNotNil(t, ptrs)
+	})
+
+	ints := make(chan int, 1)
+	ints <- 0
+	assertFails(t, "received 0, which can't be nil", func(t TestingT) {
+		AssertRecvNil(t, ints)
+	})
+	assertFails(t, "timeout waiting for channel send or receive", func(t TestingT) {
+		AssertRecvNotNil(New(t, short), ints)
+	})
+}
@@ -0,0 +1,47 @@
+package chantest
+
+import (
+	"fmt"
+	"os"
+)
+
+// FakeSignals stands in for the channel passed to signal.Notify, so that the
+// code under test can be sent signals without the process receiving any.
+// Code that calls signal.Notify itself needs to take the channel, or a
+// function to get it, instead.
+type FakeSignals struct {
+	ch chan os.Signal
+}
+
+// NewFakeSignals returns FakeSignals with a buffer of one signal, like
+// signal.Notify's documentation recommends for real ones.
+func NewFakeSignals() *FakeSignals {
+	return &FakeSignals{ch: make(chan os.Signal, 1)}
+}
+
+// C returns the channel for the code under test to receive signals from.
+func (s *FakeSignals) C() chan os.Signal {
+	return s.ch
+}
+
+// Inject asserts that sig is very quickly delivered to the channel's buffer
+// or a receiver. Unlike with signal.Notify, which drops signals that find the
+// buffer full, a signal the code under test isn't waiting for fails the test.
+func (s *FakeSignals) Inject(t TestingT, sig os.Signal, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	if !send(a, s.ch, sig) {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("timeout injecting %v; the last one wasn't received", sig), msgAndArgs...))
+	}
+}
+
+// AssertHandled injects sig, and asserts that done, which must be a channel,
+// such as one closed on shutdown, then very quickly receives or is closed.
+func (s *FakeSignals) AssertHandled(t TestingT, sig os.Signal, done interface{}, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	s.Inject(a, sig, msgAndArgs...)
+	if _, ok := a.assertRecv(done); !ok {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("timeout waiting for %v to be handled", sig), msgAndArgs...))
+	}
+}
@@ -0,0 +1,30 @@
+package chantest
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestFakeSignals(t *testing.T) {
+	sigs := NewFakeSignals()
+	shutdown := make(chan struct{})
+	go func(sigs <-chan os.Signal) {
+		for sig := range sigs {
+			if sig == syscall.SIGTERM {
+				close(shutdown)
+				return
+			}
+		}
+	}(sigs.C())
+
+	assertFails(t, "timeout waiting for hangup to be handled", func(t TestingT) {
+		sigs.AssertHandled(New(t, short), syscall.SIGHUP, shutdown)
+	})
+	sigs.AssertHandled(t, syscall.SIGTERM, shutdown)
+
+	sigs.Inject(t, os.Interrupt)
+	assertFails(t, "timeout injecting interrupt; the last one wasn't received", func(t TestingT) {
+		sigs.Inject(New(t, short), os.Interrupt)
+	})
+}
@@ -0,0 +1,100 @@
+package chantest
+
+import "fmt"
+
+// A Peeker relays values from a channel to Out, letting the test look at the
+// value that's next in line to be received from Out without receiving it.
+type Peeker[T any] struct {
+	out chan T
+	// peeks takes requests for the relaying goroutine to reply with the
+	// value it's offering to Out, if any, and a channel that's closed once it
+	// gets to offer another one.
+	peeks chan chan peek[T]
+	// done is closed once Out is.
+	done chan struct{}
+}
+
+type peek[T any] struct {
+	v       T
+	ok      bool
+	changed <-chan struct{}
+}
+
+// Peek returns a Peeker relaying values from ch, for the consumer under test
+// to receive from Out instead. Out is closed once ch is.
+func Peek[T any](ch <-chan T) *Peeker[T] {
+	p := &Peeker[T]{out: make(chan T), peeks: make(chan chan peek[T]), done: make(chan struct{})}
+	go p.relay(ch)
+	return p
+}
+
+// relay serves peeks from the same goroutine that hands values to Out, so that
+// a value is never seen by a peek after it's been received.
+func (p *Peeker[T]) relay(ch <-chan T) {
+	defer close(p.done)
+	defer close(p.out)
+	changed := make(chan struct{})
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return
+			}
+			close(changed)
+			changed = make(chan struct{})
+			for offering := true; offering; {
+				select {
+				case p.out <- v:
+					offering = false
+				case reply := <-p.peeks:
+					reply <- peek[T]{v: v, ok: true}
+				}
+			}
+		case reply := <-p.peeks:
+			reply <- peek[T]{changed: changed}
+		}
+	}
+}
+
+// Out returns the channel for the consumer to receive from.
+func (p *Peeker[T]) Out() <-chan T {
+	return p.out
+}
+
+// AssertPeek asserts that a value is, or quickly gets to be, next in line to
+// be received from Out, and that it equals want, as per reflect.DeepEqual
+// unless otherwise set with WithEqual. It returns the value, which is left for
+// the consumer to receive.
+//
+// If the consumer receives values as soon as they arrive, it may have taken
+// the value before AssertPeek gets to see it; then AssertPeek sees the next
+// one. To observe every value instead, use Tap.
+func (p *Peeker[T]) AssertPeek(t TestingT, want T, msgAndArgs ...interface{}) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	reply := make(chan peek[T], 1)
+	for {
+		select {
+		case p.peeks <- reply:
+		case <-p.done:
+			a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("channel closed, expected %#v", want), msgAndArgs...))
+			return want
+		}
+		next := <-reply
+		if next.ok {
+			if !a.equals(next.v, want) {
+				a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("next value is %#v, want %#v", next.v, want), msgAndArgs...))
+			}
+			return next.v
+		}
+		select {
+		case <-next.changed:
+		case <-p.done:
+		case <-timeout:
+			a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("timeout waiting to peek at %#v", want), msgAndArgs...))
+			return want
+		}
+	}
+}
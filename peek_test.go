@@ -0,0 +1,27 @@
+package chantest
+
+import "testing"
+
+func TestPeek(t *testing.T) {
+	ch := make(chan string, 2)
+	p := Peek(ch)
+
+	assertFails(t, `timeout waiting to peek at "a"`, func(t TestingT) {
+		p.AssertPeek(New(t, short), "a")
+	})
+	ch <- "a"
+	ch <- "b"
+	p.AssertPeek(t, "a")
+	// Peeking doesn't consume.
+	p.AssertPeek(t, "a")
+	AssertRecvEqual(t, p.Out(), "a")
+	assertFails(t, `next value is "b", want "c"`, func(t TestingT) {
+		p.AssertPeek(t, "c")
+	})
+	AssertRecvEqual(t, p.Out(), "b")
+
+	close(ch)
+	assertFails(t, `channel closed, expected "c"`, func(t TestingT) {
+		p.AssertPeek(t, "c")
+	})
+}
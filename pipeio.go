@@ -0,0 +1,70 @@
+package chantest
+
+import (
+	"fmt"
+	"io"
+)
+
+// AssertReadUnblocks asserts that a Read from r, such as one end of an
+// io.Pipe or net.Pipe, stays blocked until do is called, and that it returns
+// very quickly after that. It returns what was read, and the Read's error.
+//
+// It's AssertUnblockedBy for I/O: do typically writes to, or closes, the
+// other end.
+func AssertReadUnblocks(t TestingT, r io.Reader, do func(), msgAndArgs ...interface{}) ([]byte, error) {
+	a := asserterFor(t)
+	a.t.Helper()
+	type result struct {
+		n   int
+		err error
+	}
+	buf := make([]byte, 32*1024)
+	done := make(chan result, 1)
+	go func() {
+		n, err := r.Read(buf)
+		done <- result{n, err}
+	}()
+
+	blocked, stop := a.timeout()
+	defer stop()
+	select {
+	case res := <-done:
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("read returned %q, %v before do was called", buf[:res.n], res.err), msgAndArgs...))
+		return buf[:res.n], res.err
+	case <-blocked:
+	}
+
+	do()
+
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case res := <-done:
+		return buf[:res.n], res.err
+	case <-timeout:
+		a.t.Fatal(defaultOrCustomMessage("timeout waiting for read to return", msgAndArgs...))
+		return nil, nil
+	}
+}
+
+// AssertWriteBlocked asserts that writing data to w, such as one end of an
+// io.Pipe or net.Pipe, blocks for a very short period of time. The write is
+// left pending; the returned channel receives its error once it returns.
+func AssertWriteBlocked(t TestingT, w io.Writer, data []byte, msgAndArgs ...interface{}) <-chan error {
+	a := asserterFor(t)
+	a.t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.Write(data)
+		done <- err
+	}()
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case err := <-done:
+		done <- err
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("write of %d bytes returned %v instead of blocking", len(data), err), msgAndArgs...))
+	case <-timeout:
+	}
+	return done
+}
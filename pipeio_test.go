@@ -0,0 +1,78 @@
+package chantest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestAssertReadUnblocks(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	got, err := AssertReadUnblocks(New(t, short), server, func() {
+		go client.Write([]byte("hello"))
+	})
+	if string(got) != "hello" || err != nil {
+		t.Fatalf("got %q, %v", got, err)
+	}
+
+	pr, pw := io.Pipe()
+	if _, err := AssertReadUnblocks(New(t, short), pr, func() { pw.Close() }); err != io.EOF {
+		t.Fatalf("got %v, want EOF", err)
+	}
+
+	assertFails(t, `read returned "ready", <nil> before do was called`, func(t TestingT) {
+		AssertReadUnblocks(t, strings.NewReader("ready"), func() {})
+	})
+	assertFails(t, "timeout waiting for read to return", func(t TestingT) {
+		pr, _ := io.Pipe()
+		AssertReadUnblocks(New(t, short), pr, func() {})
+	})
+}
+
+func TestAssertWriteBlocked(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	written := AssertWriteBlocked(New(t, short), client, []byte("hello"))
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(server, buf); err != nil || string(buf) != "hello" {
+		t.Fatalf("read %q, %v", buf, err)
+	}
+	if err := AssertRecv(t, written); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	assertFails(t, "write of 2 bytes returned <nil> instead of blocking", func(t TestingT) {
+		AssertWriteBlocked(t, &bytes.Buffer{}, []byte("hi"))
+	})
+
+	// A TestingT whose Fatal returns still gets the write's error.
+	r, w := io.Pipe()
+	r.Close()
+	ct := &continueT{}
+	select {
+	case err := <-AssertWriteBlocked(ct, w, []byte("hi")):
+		if err != io.ErrClosedPipe || !strings.Contains(ct.msg, "returned io: read/write on closed pipe instead of blocking") {
+			t.Fatalf("write error %v, failure %q", err, ct.msg)
+		}
+	default:
+		t.Fatal("write error not sent")
+	}
+}
+
+// continueT records the failure without stopping the calling goroutine.
+type continueT struct {
+	fakeT
+}
+
+func (t *continueT) Fatal(args ...interface{}) {
+	t.failed = true
+	t.msg = fmt.Sprint(args...)
+}
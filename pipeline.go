@@ -0,0 +1,85 @@
+package chantest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Pipeline is a harness for a pipeline stage that receives from In and sends
+// to Out, closing Out once In is closed and its results are sent.
+type Pipeline[In, Out any] struct {
+	In  chan<- In
+	Out <-chan Out
+}
+
+// Run sends each of inputs to In, then closes it, while receiving from Out
+// until it's closed, or until nothing arrives before the timeout. It returns
+// the outputs received.
+//
+// Run fails the test if an input isn't quickly received.
+func (p Pipeline[In, Out]) Run(t TestingT, inputs []In) []Out {
+	a := asserterFor(t)
+	a.t.Helper()
+
+	fed := make(chan int, 1)
+	go func() {
+		for i, v := range inputs {
+			if !send(a, p.In, v) {
+				fed <- i
+				return
+			}
+		}
+		close(p.In)
+		fed <- len(inputs)
+	}()
+
+	var outputs []Out
+	for {
+		v, ok, received := recvOrClose(a, p.Out)
+		if !received || !ok {
+			break
+		}
+		outputs = append(outputs, v)
+	}
+
+	if n := <-fed; n < len(inputs) {
+		a.t.Fatal(fmt.Sprintf("timeout feeding input #%d %#v; outputs so far: %v", n, inputs[n], outputs))
+	}
+	return outputs
+}
+
+// AssertOutputs runs the stage with inputs, like Run, and asserts that it
+// outputs want, in order, as per reflect.DeepEqual unless otherwise set with
+// WithEqual.
+func (p Pipeline[In, Out]) AssertOutputs(t TestingT, inputs []In, want []Out, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	got := p.Run(a, inputs)
+	var diffs []string
+	for i := 0; i < len(got) || i < len(want); i++ {
+		switch {
+		case i >= len(got):
+			diffs = append(diffs, fmt.Sprintf("#%d: missing %#v", i, want[i]))
+		case i >= len(want):
+			diffs = append(diffs, fmt.Sprintf("#%d: unexpected %#v", i, got[i]))
+		case !a.equals(got[i], want[i]):
+			diffs = append(diffs, fmt.Sprintf("#%d: got %#v, want %#v", i, got[i], want[i]))
+		}
+	}
+	if len(diffs) == 0 {
+		return
+	}
+	a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("outputs differ: %s", strings.Join(diffs, "; ")), msgAndArgs...))
+}
+
+// AssertOutputSet is like AssertOutputs, but the outputs may come in any
+// order.
+func (p Pipeline[In, Out]) AssertOutputSet(t TestingT, inputs []In, want []Out, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	got := p.Run(a, inputs)
+	missing, unexpected := multisetDiff(a, want, got)
+	if len(missing) > 0 || len(unexpected) > 0 {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("outputs differ: missing %v, unexpected %v", missing, unexpected), msgAndArgs...))
+	}
+}
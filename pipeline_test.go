@@ -0,0 +1,34 @@
+package chantest
+
+import "testing"
+
+// doubler is a pipeline stage doubling its inputs, skipping those over 10.
+func doubler() Pipeline[int, int] {
+	in, out := make(chan int), make(chan int)
+	go func() {
+		defer close(out)
+		for v := range in {
+			if v <= 10 {
+				out <- 2 * v
+			}
+		}
+	}()
+	return Pipeline[int, int]{In: in, Out: out}
+}
+
+func TestPipeline(t *testing.T) {
+	assertPasses(t, func(t TestingT) {
+		doubler().AssertOutputs(t, []int{1, 20, 3}, []int{2, 6})
+		doubler().AssertOutputSet(t, []int{1, 2}, []int{4, 2})
+		doubler().AssertOutputs(t, nil, nil)
+	})
+	assertFails(t, "outputs differ: #1: got 6, want 4; #2: missing 8", func(t TestingT) {
+		doubler().AssertOutputs(t, []int{1, 3}, []int{2, 4, 8})
+	})
+	assertFails(t, "outputs differ: missing [4], unexpected [6]", func(t TestingT) {
+		doubler().AssertOutputSet(t, []int{1, 3}, []int{4, 2})
+	})
+	assertFails(t, "timeout feeding input #0 1; outputs so far: []", func(t TestingT) {
+		Pipeline[int, int]{In: make(chan int), Out: make(chan int)}.Run(New(t, short), []int{1})
+	})
+}
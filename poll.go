@@ -0,0 +1,74 @@
+package chantest
+
+import "time"
+
+// PollOptions configure how assertions that poll for a condition wait, like
+// AssertLenEventually, AssertTokensAvailable, AssertBlockedOn and
+// AssertNotReceiving. The zero value is the default behavior.
+type PollOptions struct {
+	// Interval is how often the condition is checked. The default is 1ms. It
+	// also sets how long Explore lets each action run before starting the
+	// next, and how long tick assertions wait for ticks relayed after a
+	// FakeClock advances.
+	Interval time.Duration
+	// Delay is how long to wait before checking for the first time, such as
+	// to let the code under test start.
+	//
+	// Interval and Delay are measured on the wall clock, even if the Asserter
+	// has a FakeClock, so that polling goes on while the fake time stands
+	// still.
+	Delay time.Duration
+	// Deadline, if set, is how long to keep polling, as measured by the
+	// Asserter's clock, instead of its Before duration. It also bounds the
+	// wait of AssertQuiescent.
+	Deadline time.Duration
+}
+
+// WithPolling sets how an Asserter's polling assertions wait, so that they
+// can check often in fast unit tests, and less often, for longer, in
+// integration tests.
+func WithPolling(opts PollOptions) Option {
+	return optionFunc(func(c *config) { c.polling = opts })
+}
+
+// pollTimer starts the timer for the deadline of a polling assertion.
+func (a *Asserter) pollTimer() timer {
+	if d := a.polling.Deadline; d > 0 {
+		c, stop := startTimer(a.clock, d)
+		return timer{C: c, stopFunc: stop}
+	}
+	return a.startTimer()
+}
+
+// poll calls check, after the initial delay and then at each interval, until
+// it returns true, and reports whether it did before the deadline.
+func (a *Asserter) poll(check func() bool) bool {
+	tm := a.pollTimer()
+	defer tm.stop()
+	if d := a.polling.Delay; d > 0 {
+		select {
+		case <-time.After(d):
+		case <-tm.C:
+			return check()
+		}
+	}
+	interval := a.pollInterval()
+	for {
+		if check() {
+			return true
+		}
+		select {
+		case <-time.After(interval):
+		case <-tm.C:
+			return false
+		}
+	}
+}
+
+// pollInterval returns the interval set with WithPolling, or the default.
+func (a *Asserter) pollInterval() time.Duration {
+	if d := a.polling.Interval; d > 0 {
+		return d
+	}
+	return pollInterval
+}
@@ -0,0 +1,54 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithPolling(t *testing.T) {
+	var checks []time.Time
+	a := New(t, short, WithPolling(PollOptions{Interval: 20 * time.Millisecond, Delay: 30 * time.Millisecond, Deadline: time.Second}))
+	start := time.Now()
+	if !a.poll(func() bool {
+		checks = append(checks, time.Now())
+		return len(checks) == 3
+	}) {
+		t.Fatal("poll timed out")
+	}
+	if d := checks[0].Sub(start); d < 30*time.Millisecond {
+		t.Fatalf("first check after %v, before the delay", d)
+	}
+	if d := checks[2].Sub(checks[1]); d < 20*time.Millisecond {
+		t.Fatalf("checks %v apart, less than the interval", d)
+	}
+	if d := time.Since(start); d < time.Duration(short) {
+		t.Fatalf("polled for %v; Deadline should override Before", d)
+	}
+
+	ch := make(chan int, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		ch <- 1
+	}()
+	AssertLenEventually(New(t, short, WithPolling(PollOptions{Deadline: time.Second})), ch, 1)
+
+	start = time.Now()
+	assertFails(t, "channel length didn't get to 2", func(t TestingT) {
+		AssertLenEventually(New(t, WithPolling(PollOptions{Deadline: 5 * time.Millisecond})), ch, 2)
+	})
+	if d := time.Since(start); d > time.Duration(Default) {
+		t.Fatalf("failed after %v, not at the Deadline", d)
+	}
+}
+
+func TestPollFakeClock(t *testing.T) {
+	// A FakeClock that nobody advances doesn't stop the polling.
+	checks := 0
+	a := New(t, WithClock(NewFakeClock(time.Unix(0, 0))), WithPolling(PollOptions{Delay: time.Millisecond, Deadline: time.Second}))
+	if !a.poll(func() bool {
+		checks++
+		return checks == 3
+	}) {
+		t.Fatal("poll timed out")
+	}
+}
@@ -0,0 +1,33 @@
+package chantest
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+// SweepGOMAXPROCS runs body as a subtest of t once for each of procs as
+// GOMAXPROCS, restoring it afterwards. The subtests are named after the
+// setting, e.g. "GOMAXPROCS=1", so failures show which one failed. Without
+// procs, it's 1, 2 and runtime.NumCPU(), since some bugs only show up without
+// parallelism and others only with it.
+//
+// GOMAXPROCS is process-wide, so t and its other subtests must not run in
+// parallel with the sweep.
+func SweepGOMAXPROCS(t *testing.T, body func(t *testing.T, a *Asserter), procs ...int) {
+	t.Helper()
+	if len(procs) == 0 {
+		procs = []int{1, 2, runtime.NumCPU()}
+	}
+	seen := map[int]bool{}
+	for _, n := range procs {
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		t.Run(fmt.Sprintf("GOMAXPROCS=%d", n), func(t *testing.T) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(n))
+			body(t, New(t))
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package chantest
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSweepGOMAXPROCS(t *testing.T) {
+	before := runtime.GOMAXPROCS(0)
+	var got []int
+	SweepGOMAXPROCS(t, func(t *testing.T, a *Asserter) {
+		got = append(got, runtime.GOMAXPROCS(0))
+		ch := make(chan int, 1)
+		a.AssertSend(ch, 1)
+		a.AssertRecv(ch)
+	}, 1, 3, 1)
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("ran with GOMAXPROCS %v, want [1 3]", got)
+	}
+	if after := runtime.GOMAXPROCS(0); after != before {
+		t.Fatalf("GOMAXPROCS is %d after the sweep, want %d", after, before)
+	}
+}
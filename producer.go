@@ -0,0 +1,66 @@
+package chantest
+
+import (
+	"fmt"
+	"time"
+)
+
+// A Producer is a script of sends to a channel, built with Script and started
+// with Run.
+type Producer[T any] struct {
+	ch    chan<- T
+	steps []func(a *Asserter)
+}
+
+// Script returns an empty Producer script for ch.
+//
+//	chantest.Script(ch).Send(v1).Sleep(10 * time.Millisecond).Send(v2).Close().Run(t)
+func Script[T any](ch chan<- T) *Producer[T] {
+	return &Producer[T]{ch: ch}
+}
+
+// Send adds a send of v, which fails the test unless quickly received.
+func (p *Producer[T]) Send(v T) *Producer[T] {
+	n := len(p.steps) + 1
+	return p.add(func(a *Asserter) {
+		if !send(a, p.ch, v) {
+			a.t.Fatal(fmt.Sprintf("timeout at step %d sending %#v", n, v))
+		}
+	})
+}
+
+// Sleep adds a pause of d, as measured by the Asserter's Clock if Run is given
+// one.
+func (p *Producer[T]) Sleep(d time.Duration) *Producer[T] {
+	return p.add(func(a *Asserter) {
+		c, stop := startTimer(a.clock, d)
+		defer stop()
+		<-c
+	})
+}
+
+// Do adds a call to f, such as to synchronize with the test.
+func (p *Producer[T]) Do(f func()) *Producer[T] {
+	return p.add(func(*Asserter) { f() })
+}
+
+// Close adds closing the channel.
+func (p *Producer[T]) Close() *Producer[T] {
+	return p.add(func(*Asserter) { close(p.ch) })
+}
+
+func (p *Producer[T]) add(step func(a *Asserter)) *Producer[T] {
+	p.steps = append(p.steps, step)
+	return p
+}
+
+// Run runs the script in a new goroutine, as started by Go, whose Wait reports
+// the first failed step.
+func (p *Producer[T]) Run(t TestingT) *Goroutine {
+	unwrap(t).Helper()
+	return Go(t, func(a *Asserter) {
+		for _, step := range p.steps {
+			step(a)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScript(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	a := New(t, WithClock(clock))
+	ch := make(chan int)
+	g := Script(ch).Send(1).Sleep(time.Second).Send(2).Close().Run(a)
+
+	if got := Recv(a, ch); got != 1 {
+		t.Fatalf("got %d, want 1", got)
+	}
+	clock.BlockUntil(1)
+	NoRecv(New(t, short), ch)
+	clock.Advance(time.Second)
+	if got := Recv(t, ch); got != 2 {
+		t.Fatalf("got %d, want 2", got)
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("channel not closed")
+	}
+	g.Wait()
+}
+
+func TestScriptFailure(t *testing.T) {
+	assertFails(t, "timeout at step 3 sending 2", func(t TestingT) {
+		ch := make(chan int, 1)
+		Script(ch).Send(1).Do(func() {}).Send(2).Run(New(t, short)).Wait()
+	})
+}
@@ -0,0 +1,146 @@
+package chantest
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// A Property is a property of a pipeline stage's outputs for given inputs. It
+// returns a description of how outputs violate it, or "" if they don't.
+type Property[In, Out any] func(inputs []In, outputs []Out) string
+
+// A PipelineCheck checks properties of a pipeline stage on random inputs, like
+// testing/quick.Check.
+type PipelineCheck[In, Out any] struct {
+	// Start starts a fresh instance of the stage for each run.
+	Start func() Pipeline[In, Out]
+	// Gen generates a random input.
+	Gen func(r *rand.Rand) In
+	// Runs is how many random input sequences to try. The default is 100.
+	Runs int
+	// MaxLen is the longest input sequence to try. The default is 20.
+	MaxLen int
+}
+
+// Check runs the stage with random input sequences, as with Pipeline.Run, and
+// fails at the first sequence for which any of props is violated, or for which
+// the stage doesn't quickly receive an input.
+//
+// The failing sequence is shrunk by removing inputs while it still fails, and
+// reported with the seed to reproduce it with, through WithSeed on t. Without
+// a seed, a random one is used.
+func (c PipelineCheck[In, Out]) Check(t TestingT, props ...Property[In, Out]) {
+	a := asserterFor(t)
+	a.t.Helper()
+	runs, maxLen := c.Runs, c.MaxLen
+	if runs == 0 {
+		runs = 100
+	}
+	if maxLen == 0 {
+		maxLen = 20
+	}
+	seed := time.Now().UnixNano()
+	if a.seed != nil {
+		seed = *a.seed
+	}
+	r := rand.New(rand.NewSource(seed))
+
+	for i := 0; i < runs; i++ {
+		inputs := make([]In, r.Intn(maxLen+1))
+		for j := range inputs {
+			inputs[j] = c.Gen(r)
+		}
+		failure := c.try(a, inputs, props)
+		if failure == "" {
+			continue
+		}
+		n := len(inputs)
+		inputs, failure = c.shrink(a, inputs, failure, props)
+		a.t.Fatal(fmt.Sprintf("run %d with seed %d: inputs %#v, shrunk from %d: %s", i+1, seed, inputs, n, failure))
+		return
+	}
+}
+
+// shrink removes chunks of inputs, halving their size down to single inputs,
+// while the check still fails. It returns the shortest failing inputs found,
+// with their failure.
+func (c PipelineCheck[In, Out]) shrink(a *Asserter, inputs []In, failure string, props []Property[In, Out]) ([]In, string) {
+	for size := len(inputs) / 2; size > 0; size /= 2 {
+		for start := 0; start+size <= len(inputs); {
+			candidate := append(append([]In(nil), inputs[:start]...), inputs[start+size:]...)
+			if f := c.try(a, candidate, props); f != "" {
+				inputs, failure = candidate, f
+				continue
+			}
+			start += size
+		}
+	}
+	return inputs, failure
+}
+
+// try runs a fresh stage with inputs, and returns how it fails, if it does.
+func (c PipelineCheck[In, Out]) try(a *Asserter, inputs []In, props []Property[In, Out]) string {
+	var outputs []Out
+	rec := &recordT{}
+	Go(&Asserter{t: rec, config: a.config}, func(a *Asserter) {
+		outputs = c.Start().Run(a, inputs)
+	}).Wait()
+	if rec.failure != nil {
+		return *rec.failure
+	}
+	for _, prop := range props {
+		if violation := prop(inputs, outputs); violation != "" {
+			return violation
+		}
+	}
+	return ""
+}
+
+// SameOrder is the property that the outputs are f applied to each input, in
+// order, as per reflect.DeepEqual.
+func SameOrder[In, Out any](f func(In) Out) Property[In, Out] {
+	return func(inputs []In, outputs []Out) string {
+		want := mapAll(inputs, f)
+		if len(outputs) != len(want) {
+			return fmt.Sprintf("outputs %#v, want %#v", outputs, want)
+		}
+		for i := range want {
+			if !(&Asserter{}).equals(outputs[i], want[i]) {
+				return fmt.Sprintf("outputs %#v, want %#v", outputs, want)
+			}
+		}
+		return ""
+	}
+}
+
+// PermutationOf is the property that the outputs are f applied to each input,
+// in any order, as per reflect.DeepEqual.
+func PermutationOf[In, Out any](f func(In) Out) Property[In, Out] {
+	return func(inputs []In, outputs []Out) string {
+		want := mapAll(inputs, f)
+		if missing, unexpected := multisetDiff(&Asserter{}, want, outputs); len(missing) > 0 || len(unexpected) > 0 {
+			return fmt.Sprintf("outputs %#v aren't a permutation of %#v: missing %#v, unexpected %#v", outputs, want, missing, unexpected)
+		}
+		return ""
+	}
+}
+
+// NoLoss is the property that f applied to each input is among the outputs,
+// as per reflect.DeepEqual, which may also have others.
+func NoLoss[In, Out any](f func(In) Out) Property[In, Out] {
+	return func(inputs []In, outputs []Out) string {
+		if missing, _ := multisetDiff(&Asserter{}, mapAll(inputs, f), outputs); len(missing) > 0 {
+			return fmt.Sprintf("outputs %#v are missing %#v", outputs, missing)
+		}
+		return ""
+	}
+}
+
+func mapAll[In, Out any](inputs []In, f func(In) Out) []Out {
+	outputs := make([]Out, len(inputs))
+	for i, v := range inputs {
+		outputs[i] = f(v)
+	}
+	return outputs
+}
@@ -0,0 +1,72 @@
+package chantest
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+)
+
+// stage returns a PipelineCheck of a stage doubling ints with process.
+func stage(process func(in <-chan int, out chan<- int)) PipelineCheck[int, int] {
+	return PipelineCheck[int, int]{
+		Start: func() Pipeline[int, int] {
+			in, out := make(chan int), make(chan int, 100)
+			go func() {
+				defer close(out)
+				process(in, out)
+			}()
+			return Pipeline[int, int]{In: in, Out: out}
+		},
+		Gen: func(r *rand.Rand) int { return r.Intn(10) },
+	}
+}
+
+func double(v int) int { return v * 2 }
+
+func TestPipelineCheck(t *testing.T) {
+	assertPasses(t, func(t TestingT) {
+		stage(func(in <-chan int, out chan<- int) {
+			for v := range in {
+				out <- v * 2
+			}
+		}).Check(t, SameOrder(double), PermutationOf(double), NoLoss(double))
+	})
+
+	lossy := stage(func(in <-chan int, out chan<- int) {
+		for v := range in {
+			if v != 7 {
+				out <- v * 2
+			}
+		}
+	})
+	assertFails(t, "inputs []int{7}, shrunk from ", func(t TestingT) {
+		lossy.Check(New(t, WithSeed(1)), NoLoss(double))
+	})
+
+	// Swaps the first two values.
+	swapping := stage(func(in <-chan int, out chan<- int) {
+		first, ok := <-in
+		if !ok {
+			return
+		}
+		for v := range in {
+			out <- v * 2
+			if ok {
+				out <- first * 2
+				ok = false
+			}
+		}
+		if ok {
+			out <- first * 2
+		}
+	})
+	assertPasses(t, func(t TestingT) {
+		swapping.Check(t, PermutationOf(double))
+	})
+	msg, failed := failure(func(t TestingT) {
+		swapping.Check(New(t, WithSeed(2)), SameOrder(double))
+	})
+	if !failed || !regexp.MustCompile(`with seed 2: inputs \[\]int\{\d, \d\}, shrunk from \d+: outputs`).MatchString(msg) {
+		t.Fatalf("unexpected failure: %q", msg)
+	}
+}
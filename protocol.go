@@ -0,0 +1,178 @@
+package chantest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Protocol is a state machine of the messages allowed on a channel. Each
+// message is classified into a kind, and each kind is only allowed in some
+// states, moving the machine to another state.
+//
+// For example, a protocol of a Hello, any number of Data, and a Bye before
+// closing:
+//
+//	p := NewProtocol("start", func(m Msg) string { return m.Kind }).
+//		Allow("start", "Hello", "open").
+//		Allow("open", "Data", "open").
+//		Allow("open", "Bye", "done").
+//		Final("done")
+type Protocol[T any] struct {
+	initial  string
+	classify func(T) string
+	// allowed maps states to kinds to next states.
+	allowed map[string]map[string]string
+	// final is nil if closing is allowed in any state.
+	final map[string]bool
+}
+
+// NewProtocol returns a Protocol starting at the initial state, which allows
+// nothing until Allow is called.
+func NewProtocol[T any](initial string, classify func(T) string) *Protocol[T] {
+	return &Protocol[T]{initial: initial, classify: classify, allowed: map[string]map[string]string{}}
+}
+
+// Allow allows messages of the given kind in the from state, moving to the to
+// state. It returns p.
+func (p *Protocol[T]) Allow(from, kind, to string) *Protocol[T] {
+	if p.allowed[from] == nil {
+		p.allowed[from] = map[string]string{}
+	}
+	p.allowed[from][kind] = to
+	return p
+}
+
+// Final sets the states in which the channel may be closed, which otherwise
+// it may be in any state. It returns p.
+func (p *Protocol[T]) Final(states ...string) *Protocol[T] {
+	if p.final == nil {
+		p.final = map[string]bool{}
+	}
+	for _, s := range states {
+		p.final[s] = true
+	}
+	return p
+}
+
+// An Enforced channel relays values that follow a Protocol, like an
+// Instrumented channel does.
+type Enforced[T any] struct {
+	p       *Protocol[T]
+	t       TestingT
+	in, out chan T
+
+	mu        sync.Mutex
+	state     string
+	violation string
+}
+
+// Enforce returns an Enforced channel with ch as its buffer. ch must not be
+// used directly afterwards.
+//
+// Once a value or a close breaks the protocol, the values after it are
+// dropped. If t has an Error method, like *testing.T, which, unlike Fatal, can
+// be called from any goroutine, the violation is reported with it right away.
+// Either way, AssertFollowed reports it.
+func (p *Protocol[T]) Enforce(t TestingT, ch chan T) *Enforced[T] {
+	c := &Enforced[T]{p: p, t: unwrap(t), in: make(chan T), out: make(chan T), state: p.initial}
+	go c.relayIn(ch)
+	go c.relayOut(ch)
+	return c
+}
+
+// In returns the channel for producers to send to, and eventually close.
+func (c *Enforced[T]) In() chan<- T {
+	return c.in
+}
+
+// Out returns the channel for consumers to receive from.
+func (c *Enforced[T]) Out() <-chan T {
+	return c.out
+}
+
+// State returns the protocol's current state.
+func (c *Enforced[T]) State() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+func (c *Enforced[T]) relayIn(buf chan T) {
+	for v := range c.in {
+		if c.step(v) {
+			buf <- v
+		}
+	}
+	c.mu.Lock()
+	if c.violation == "" && c.p.final != nil && !c.p.final[c.state] {
+		c.violate(fmt.Sprintf("close in state %q; it may only be closed in %s", c.state, setList(c.p.final)))
+	}
+	c.mu.Unlock()
+	close(buf)
+}
+
+func (c *Enforced[T]) relayOut(buf chan T) {
+	for v := range buf {
+		c.out <- v
+	}
+	close(c.out)
+}
+
+// step moves the state machine for v, and reports whether v is allowed.
+func (c *Enforced[T]) step(v T) bool {
+	kind := c.p.classify(v)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.violation != "" {
+		return false
+	}
+	next, ok := c.p.allowed[c.state][kind]
+	if !ok {
+		allowed := map[string]bool{}
+		for kind := range c.p.allowed[c.state] {
+			allowed[kind] = true
+		}
+		c.violate(fmt.Sprintf("%s (%#v) in state %q, which allows %s", kind, v, c.state, setList(allowed)))
+		return false
+	}
+	c.state = next
+	return true
+}
+
+// violate records the violation, with c.mu held.
+func (c *Enforced[T]) violate(msg string) {
+	c.violation = "protocol violation: " + msg
+	if e, ok := c.t.(errorer); ok {
+		e.Error(c.violation)
+	}
+}
+
+// AssertFollowed asserts that the protocol hasn't been broken.
+func (c *Enforced[T]) AssertFollowed(t TestingT, msgAndArgs ...interface{}) {
+	t = unwrap(t)
+	t.Helper()
+	c.mu.Lock()
+	violation := c.violation
+	c.mu.Unlock()
+	if violation != "" {
+		t.Fatal(defaultOrCustomMessage(violation, msgAndArgs...))
+	}
+}
+
+type errorer interface {
+	Error(...interface{})
+}
+
+func setList(set map[string]bool) string {
+	if len(set) == 0 {
+		return "nothing"
+	}
+	var list []string
+	for s := range set {
+		list = append(list, s)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
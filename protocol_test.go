@@ -0,0 +1,81 @@
+package chantest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+type protocolMsg struct {
+	Kind string
+	N    int
+}
+
+func testProtocol() *Protocol[protocolMsg] {
+	return NewProtocol("start", func(m protocolMsg) string { return m.Kind }).
+		Allow("start", "Hello", "open").
+		Allow("open", "Data", "open").
+		Allow("open", "Bye", "done").
+		Final("done")
+}
+
+func TestProtocol(t *testing.T) {
+	c := testProtocol().Enforce(t, make(chan protocolMsg, 10))
+	for _, m := range []protocolMsg{{"Hello", 0}, {"Data", 1}, {"Data", 2}, {"Bye", 0}} {
+		c.In() <- m
+	}
+	close(c.In())
+	for range c.Out() {
+	}
+	c.AssertFollowed(t)
+	if got := c.State(); got != "done" {
+		t.Fatalf("state %q, want done", got)
+	}
+}
+
+// errorT is a fakeT that also records Error calls.
+type errorT struct {
+	fakeT
+	mu     sync.Mutex
+	errors []string
+}
+
+func (t *errorT) Error(args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func TestProtocolViolations(t *testing.T) {
+	et := &errorT{}
+	c := testProtocol().Enforce(et, make(chan protocolMsg, 10))
+	c.In() <- protocolMsg{"Data", 1}
+	c.In() <- protocolMsg{"Hello", 0}
+	close(c.In())
+	if _, ok := <-c.Out(); ok {
+		t.Fatal("violating value relayed")
+	}
+
+	want := `protocol violation: Data (chantest.protocolMsg{Kind:"Data", N:1}) in state "start", which allows Hello`
+	et.mu.Lock()
+	errors := et.errors
+	et.mu.Unlock()
+	if len(errors) != 1 || errors[0] != want {
+		t.Fatalf("got errors %q, want %q", errors, want)
+	}
+	assertFails(t, want, func(t TestingT) {
+		c.AssertFollowed(t)
+	})
+
+	c = testProtocol().Enforce(&fakeT{}, make(chan protocolMsg, 10))
+	c.In() <- protocolMsg{"Hello", 0}
+	close(c.In())
+	for range c.Out() {
+	}
+	assertFails(t, `protocol violation: close in state "open"; it may only be closed in done`, func(t TestingT) {
+		c.AssertFollowed(t)
+	})
+	if got := c.State(); got != "open" {
+		t.Fatalf("state %q, want open", got)
+	}
+}
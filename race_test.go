@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package chantest
+
+// raceEnabled is set when tests run with the race detector, which randomly
+// drops values put in a sync.Pool.
+const raceEnabled = true
@@ -0,0 +1,57 @@
+package chantest
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// raceJitter is the most times each operation of a Race yields the processor
+// before running, to vary which one goes first.
+const raceJitter = 100
+
+// Race runs fnA and fnB concurrently, n times, releasing both at once and then
+// delaying each by a random number of scheduler yields, to tease out data
+// races around channel hand-offs. Run it under -race.
+//
+// Race fails if an iteration doesn't quickly complete. The jitter comes from
+// Asserter.Rand, so it's reproducible with WithSeed.
+func Race(t TestingT, n int, fnA, fnB func()) {
+	a := asserterFor(t)
+	a.t.Helper()
+	r := a.Rand()
+	for i := 0; i < n; i++ {
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for _, op := range []struct {
+			fn     func()
+			yields int
+		}{{fnA, r.Intn(raceJitter)}, {fnB, r.Intn(raceJitter)}} {
+			op := op
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				for j := 0; j < op.yields; j++ {
+					runtime.Gosched()
+				}
+				op.fn()
+			}()
+		}
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		close(start)
+
+		tm := a.startTimer()
+		select {
+		case <-done:
+			tm.stop()
+		case <-tm.C:
+			a.t.Fatal(fmt.Sprintf("timeout waiting for iteration %d of %d to complete", i+1, n))
+			return
+		}
+	}
+}
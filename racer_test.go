@@ -0,0 +1,25 @@
+package chantest
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestRace(t *testing.T) {
+	ch := make(chan int)
+	var sent, received int32
+	Race(t, 50, func() {
+		ch <- 1
+		atomic.AddInt32(&sent, 1)
+	}, func() {
+		atomic.AddInt32(&received, int32(<-ch))
+	})
+	if sent != 50 || received != 50 {
+		t.Fatalf("sent %d, received %d, want 50", sent, received)
+	}
+
+	assertFails(t, "timeout waiting for iteration 1 of 3 to complete", func(t TestingT) {
+		Race(New(t, short), 3, func() { ch <- 1 }, func() {})
+	})
+	<-ch
+}
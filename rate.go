@@ -0,0 +1,58 @@
+package chantest
+
+import (
+	"fmt"
+	"time"
+)
+
+// AssertRateAtMost receives from ch for window, and fails if more than n
+// values arrive. It returns the received values.
+//
+// The window is measured by the Asserter's Clock if t is one.
+func AssertRateAtMost[T any](t TestingT, ch <-chan T, n int, window time.Duration, msgAndArgs ...interface{}) []T {
+	a := asserterFor(t)
+	a.t.Helper()
+	got, _ := recvForWindow(a, ch, -1, window)
+	if len(got) > n {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("received %d values in %v, want at most %d", len(got), window, n), msgAndArgs...))
+	}
+	return got
+}
+
+// AssertRateAtLeast fails unless n values arrive at ch within window. It
+// returns as soon as they do, with the received values.
+//
+// The window is measured by the Asserter's Clock if t is one.
+func AssertRateAtLeast[T any](t TestingT, ch <-chan T, n int, window time.Duration, msgAndArgs ...interface{}) []T {
+	a := asserterFor(t)
+	a.t.Helper()
+	got, closed := recvForWindow(a, ch, n, window)
+	if len(got) < n {
+		msg := fmt.Sprintf("received %d values in %v, want at least %d", len(got), window, n)
+		if closed {
+			msg += "; channel closed"
+		}
+		a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+	}
+	return got
+}
+
+// recvForWindow receives from ch until window elapses, ch is closed, or,
+// unless it's negative, n values are received.
+func recvForWindow[T any](a *Asserter, ch <-chan T, n int, window time.Duration) (got []T, closed bool) {
+	timeout, stop := startTimer(a.clock, window)
+	defer stop()
+	for n < 0 || len(got) < n {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return got, true
+			}
+			a.matched(ch, v)
+			got = append(got, v)
+		case <-timeout:
+			return got, false
+		}
+	}
+	return got, false
+}
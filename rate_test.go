@@ -0,0 +1,34 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssertRate(t *testing.T) {
+	tick := func(n int, every time.Duration) <-chan int {
+		ch := make(chan int)
+		go func() {
+			for i := 0; i < n; i++ {
+				time.Sleep(every)
+				ch <- i
+			}
+			close(ch)
+		}()
+		return ch
+	}
+
+	assertPasses(t, func(t TestingT) {
+		got := AssertRateAtMost(t, tick(2, time.Millisecond), 2, 30*time.Millisecond)
+		if len(got) != 2 {
+			t.Fatal("unexpected values", got)
+		}
+		AssertRateAtLeast(t, tick(3, time.Millisecond), 3, time.Second)
+	})
+	assertFails(t, "received 3 values in 30ms, want at most 2", func(t TestingT) {
+		AssertRateAtMost(t, tick(3, time.Millisecond), 2, 30*time.Millisecond)
+	})
+	assertFails(t, "received 2 values in 1s, want at least 3; channel closed", func(t TestingT) {
+		AssertRateAtLeast(t, tick(2, time.Millisecond), 3, time.Second)
+	})
+}
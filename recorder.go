@@ -0,0 +1,224 @@
+package chantest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// An EventKind is the kind of operation an Event records.
+type EventKind int
+
+// The kinds of recorded events.
+const (
+	EventSend EventKind = iota
+	EventRecv
+	EventClose
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventSend:
+		return "send"
+	case EventRecv:
+		return "recv"
+	case EventClose:
+		return "close"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// An Event is an operation on a recorded channel.
+type Event struct {
+	// Seq is the event's position in the trace, starting at 0. Events from
+	// different channels are ordered by it, even if their times are equal.
+	Seq     int
+	Channel string
+	Kind    EventKind
+	// Value is nil for EventClose.
+	Value interface{}
+	Time  time.Time
+}
+
+func (e Event) String() string {
+	if e.Kind == EventClose {
+		return fmt.Sprintf("#%d %s close", e.Seq, e.Channel)
+	}
+	return fmt.Sprintf("#%d %s %s %v", e.Seq, e.Channel, e.Kind, e.Value)
+}
+
+// A Trace is a sequence of recorded events.
+type Trace []Event
+
+// Channel returns the events on the named channel.
+func (tr Trace) Channel(name string) Trace {
+	return tr.filter(func(e Event) bool { return e.Channel == name })
+}
+
+// Kind returns the events of the given kind.
+func (tr Trace) Kind(kind EventKind) Trace {
+	return tr.filter(func(e Event) bool { return e.Kind == kind })
+}
+
+// Values returns the values of the events.
+func (tr Trace) Values() []interface{} {
+	values := make([]interface{}, 0, len(tr))
+	for _, e := range tr {
+		values = append(values, e.Value)
+	}
+	return values
+}
+
+// find returns the first event of the given kind on the named channel with a
+// value equal to v, as per equals.
+func (tr Trace) find(channel string, kind EventKind, v interface{}, equals func(got, want interface{}) bool) (Event, bool) {
+	for _, e := range tr {
+		if e.Channel == channel && e.Kind == kind && equals(e.Value, v) {
+			return e, true
+		}
+	}
+	return Event{}, false
+}
+
+func (tr Trace) filter(keep func(Event) bool) Trace {
+	var kept Trace
+	for _, e := range tr {
+		if keep(e) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// A Recorder records the events on a set of channels, as passed to Record,
+// into a single Trace, for verifying what happened after the fact.
+type Recorder struct {
+	clock Clock
+
+	mu     sync.Mutex
+	events Trace
+	// changed is closed and replaced whenever an event is recorded.
+	changed chan struct{}
+}
+
+// NewRecorder returns an empty Recorder. Of opts, only WithClock is relevant;
+// it sets the clock events are timed with.
+func NewRecorder(opts ...Option) *Recorder {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	return &Recorder{clock: cfg.clock, changed: make(chan struct{})}
+}
+
+// Record returns an Instrumented channel with ch as its buffer, whose events
+// are recorded by r under name.
+func Record[T any](r *Recorder, name string, ch chan T) *Instrumented[T] {
+	c := Instrument(ch, WithClock(r.clock))
+	c.rec, c.name = r, name
+	return c
+}
+
+func (r *Recorder) record(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.Seq = len(r.events)
+	r.events = append(r.events, e)
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// Trace returns the events recorded so far.
+func (r *Recorder) Trace() Trace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(Trace(nil), r.events...)
+}
+
+// AssertEvent asserts that an event of the given kind, with a value equal to v
+// as per reflect.DeepEqual unless otherwise set with WithEqual, very quickly
+// is, or gets, recorded on the named channel. It returns the first such event.
+func (r *Recorder) AssertEvent(t TestingT, channel string, kind EventKind, v interface{}, msgAndArgs ...interface{}) Event {
+	a := asserterFor(t)
+	a.t.Helper()
+	var found Event
+	r.assertEventually(a, func(tr Trace) (bool, string) {
+		var ok bool
+		found, ok = tr.find(channel, kind, v, a.equals)
+		return ok, fmt.Sprintf("no %s of %#v on %s; trace: %v", kind, v, channel, tr)
+	}, msgAndArgs...)
+	return found
+}
+
+// AssertBefore asserts that first and then, matched against recorded events
+// like in AssertEvent, very quickly are, or get, recorded, and that first was
+// recorded before then. Only the Channel, Kind and Value of first and then are
+// matched.
+//
+// Events are ordered by Seq rather than by Time, so that events recorded at
+// the same time, such as with a FakeClock, are still ordered.
+func (r *Recorder) AssertBefore(t TestingT, first, then Event, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	var gotFirst, gotThen Event
+	r.assertEventually(a, func(tr Trace) (bool, string) {
+		var okFirst, okThen bool
+		gotFirst, okFirst = tr.find(first.Channel, first.Kind, first.Value, a.equals)
+		gotThen, okThen = tr.find(then.Channel, then.Kind, then.Value, a.equals)
+		return okFirst && okThen, fmt.Sprintf("no %s of %#v on %s and %s of %#v on %s; trace: %v",
+			first.Kind, first.Value, first.Channel, then.Kind, then.Value, then.Channel, tr)
+	}, msgAndArgs...)
+	if gotFirst.Seq > gotThen.Seq {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("%v happened after %v; trace: %v", gotFirst, gotThen, r.Trace()), msgAndArgs...))
+	}
+}
+
+// AssertValues asserts that the values of the events of the given kind on the
+// named channel very quickly are, or get to be, equal to want, as per
+// reflect.DeepEqual unless otherwise set with WithEqual.
+func (r *Recorder) AssertValues(t TestingT, channel string, kind EventKind, want []interface{}, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	r.assertEventually(a, func(tr Trace) (bool, string) {
+		got := tr.Channel(channel).Kind(kind).Values()
+		return valuesEqual(a, got, want), fmt.Sprintf("%s values on %s are %v, want %v", kind, channel, got, want)
+	}, msgAndArgs...)
+}
+
+// valuesEqual reports whether got and want have equal values, as per a.equals,
+// at each index.
+func valuesEqual(a *Asserter, got, want []interface{}) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !a.equals(got[i], want[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// assertEventually fails unless check passes on the trace before the
+// Asserter's timeout. check also returns the failure message.
+func (r *Recorder) assertEventually(a *Asserter, check func(Trace) (bool, string), msgAndArgs ...interface{}) {
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	for {
+		r.mu.Lock()
+		tr, changed := r.events, r.changed
+		r.mu.Unlock()
+		ok, msg := check(tr)
+		if ok {
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+			return
+		}
+	}
+}
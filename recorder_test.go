@@ -0,0 +1,85 @@
+package chantest
+
+import "testing"
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	orders := Record(r, "orders", make(chan string))
+	acks := Record(r, "acks", make(chan int, 1))
+
+	go func() {
+		for order := range orders.Out() {
+			acks.In() <- len(order)
+		}
+		close(acks.In())
+	}()
+
+	Send(t, orders.In(), "pizza")
+	Recv(t, acks.Out())
+	close(orders.In())
+
+	assertPasses(t, func(t TestingT) {
+		e := r.AssertEvent(t, "acks", EventRecv, 5)
+		if e.Channel != "acks" {
+			t.Fatal("unexpected event", e)
+		}
+		r.AssertEvent(t, "acks", EventClose, nil)
+		r.AssertValues(t, "orders", EventSend, []interface{}{"pizza"})
+	})
+	assertFails(t, `no send of "burger" on orders`, func(t TestingT) {
+		r.AssertEvent(New(t, short), "orders", EventSend, "burger")
+	})
+	assertFails(t, "recv values on acks are [5], want [6]", func(t TestingT) {
+		r.AssertValues(New(t, short), "acks", EventRecv, []interface{}{6})
+	})
+
+	tr := r.Trace()
+	sent := tr.Channel("orders").Kind(EventSend)
+	acked := tr.Channel("acks").Kind(EventSend)
+	if len(sent) != 1 || len(acked) != 1 || sent[0].Seq >= acked[0].Seq {
+		t.Fatalf("unexpected trace: %v", tr)
+	}
+}
+
+func TestRecorderAssertBefore(t *testing.T) {
+	r := NewRecorder()
+	orders := Record(r, "orders", make(chan string, 1))
+	acks := Record(r, "acks", make(chan int, 1))
+
+	Send(t, orders.In(), "pizza")
+	r.AssertEvent(t, "orders", EventSend, "pizza")
+	Send(t, acks.In(), 5)
+
+	sentOrder := Event{Channel: "orders", Kind: EventSend, Value: "pizza"}
+	sentAck := Event{Channel: "acks", Kind: EventSend, Value: 5}
+	assertPasses(t, func(t TestingT) {
+		r.AssertBefore(t, sentOrder, sentAck)
+	})
+	assertFails(t, `#1 acks send 5 happened after #0 orders send pizza`, func(t TestingT) {
+		r.AssertBefore(t, sentAck, sentOrder)
+	})
+	assertFails(t, `no send of 5 on acks and send of "burger" on orders`, func(t TestingT) {
+		r.AssertBefore(New(t, short), sentAck, Event{Channel: "orders", Kind: EventSend, Value: "burger"})
+	})
+}
+
+func TestRecorderWithEqual(t *testing.T) {
+	type order struct {
+		Item string
+		ID   int
+	}
+	r := NewRecorder()
+	orders := Record(r, "orders", make(chan order, 1))
+	Send(t, orders.In(), order{"pizza", 42})
+
+	a := New(t, WithEqual(Ignore("ID")))
+	assertPasses(t, func(t TestingT) {
+		a := rebind(t, a)
+		r.AssertEvent(a, "orders", EventSend, order{Item: "pizza"})
+		r.AssertValues(a, "orders", EventSend, []interface{}{order{Item: "pizza"}})
+		r.AssertBefore(a, Event{Channel: "orders", Kind: EventSend, Value: order{Item: "pizza"}}, Event{Channel: "orders", Kind: EventSend, Value: order{Item: "pizza", ID: 7}})
+	})
+	assertFails(t, `no send of chantest.order{Item:"burger", ID:0} on orders`, func(t TestingT) {
+		r.AssertEvent(New(t, short, WithEqual(Ignore("ID"))), "orders", EventSend, order{Item: "burger"})
+	})
+}
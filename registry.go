@@ -0,0 +1,118 @@
+package chantest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An Observable is a channel whose activity an Asserter can follow once
+// registered with it. Instrumented channels are Observable.
+type Observable interface {
+	activity() activity
+	// outChan returns the channel consumers receive from.
+	outChan() interface{}
+	// receivedValues returns the values received from the channel so far, if
+	// it knows them.
+	receivedValues() []interface{}
+	// drain receives the values in the channel when called, waiting for a's
+	// timeout at most for each.
+	drain(a *Asserter) []interface{}
+	// awaitClose receives the values in the channel until it's closed, or
+	// until expired is closed, and reports whether it was closed.
+	awaitClose(expired <-chan struct{}) (stranded []interface{}, closed bool)
+}
+
+// activity is an Observable's last event, and a description of its state.
+type activity struct {
+	// last is zero if there's been no activity.
+	last  time.Time
+	state string
+}
+
+type registry struct {
+	mu       sync.Mutex
+	names    []string
+	channels []Observable
+	// matched holds the values Strict assertions received, by channel.
+	matched map[uintptr][]interface{}
+}
+
+// Register adds c, under name, to the channels the Asserter follows. Asserters
+// created from it, including the ones passed to Go bodies, follow it too.
+func (a *Asserter) Register(name string, c Observable) {
+	if a.registry == nil {
+		a.registry = &registry{}
+	}
+	r := a.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = append(r.names, name)
+	r.channels = append(r.channels, c)
+}
+
+// channelList returns the registered channels and their names.
+func (r *registry) channelList() ([]string, []Observable) {
+	if r == nil {
+		return nil, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.names...), append([]Observable(nil), r.channels...)
+}
+
+// snapshot returns the activity of each registered channel, by name, and the
+// names in registration order.
+func (r *registry) snapshot() ([]string, map[string]activity) {
+	names, channels := r.channelList()
+	activities := make(map[string]activity, len(channels))
+	for i, c := range channels {
+		activities[names[i]] = c.activity()
+	}
+	return names, activities
+}
+
+// AssertQuiescent asserts that, before the Asserter's timeout, or the Deadline
+// set with WithPolling, there's a period of d with no activity on any
+// registered channel, starting no earlier than the call. The timeout should be
+// comfortably longer than d.
+//
+// Useful as a point at which an event-driven system has settled, such as
+// before checking that nothing else was sent.
+func (a *Asserter) AssertQuiescent(d time.Duration, msgAndArgs ...interface{}) {
+	a.t.Helper()
+	tm := a.pollTimer()
+	defer tm.stop()
+	timeout := tm.C
+	start := a.clock.Now()
+	for {
+		names, activities := a.registry.snapshot()
+		last := start
+		for _, act := range activities {
+			if act.last.After(last) {
+				last = act.last
+			}
+		}
+		quiet := a.clock.Now().Sub(last)
+		if quiet >= d {
+			return
+		}
+
+		wait, stopWait := startTimer(a.clock, d-quiet)
+		select {
+		case <-wait:
+			stopWait()
+		case <-timeout:
+			stopWait()
+			var active []string
+			for _, name := range names {
+				if act := activities[name]; act.last.After(start) {
+					active = append(active, fmt.Sprintf("%s (%s)", name, act.state))
+				}
+			}
+			a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("no quiet period of %v before timeout; active channels: %s", d, strings.Join(active, ", ")), msgAndArgs...))
+			return
+		}
+	}
+}
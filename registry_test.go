@@ -0,0 +1,56 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssertQuiescent(t *testing.T) {
+	ticks := Instrument(make(chan int))
+	idle := Instrument(make(chan int))
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for range ticks.Out() {
+		}
+	}()
+	tick := func(n int) {
+		for i := 0; i < n; i++ {
+			select {
+			case ticks.In() <- i:
+			case <-stop:
+				return
+			}
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+
+	a := New(t, Before(time.Second))
+	a.Register("ticks", ticks)
+	a.Register("idle", idle)
+
+	go tick(10)
+	assertPasses(t, func(t TestingT) {
+		rebind(t, a).AssertQuiescent(20 * time.Millisecond)
+	})
+
+	go tick(1 << 30)
+	assertFails(t, "no quiet period of 50ms before timeout; active channels: ticks (", func(t TestingT) {
+		rebind(t, Before(100*time.Millisecond).on(a)).AssertQuiescent(50 * time.Millisecond)
+	})
+}
+
+func TestAssertQuiescentFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	a := New(t, WithClock(clock), Before(time.Second))
+	a.Register("ch", Instrument(make(chan int)))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.AssertQuiescent(10 * time.Millisecond)
+	}()
+	clock.BlockUntil(2)
+	clock.Advance(10 * time.Millisecond)
+	<-done
+}
@@ -0,0 +1,57 @@
+package chantest
+
+import (
+	"fmt"
+	"time"
+)
+
+// Replay sends the values of tr's send events to ch, in order and with their
+// original relative timing divided by speed, then closes ch if tr has a close
+// event. A speed of 0 sends the values without waiting in between.
+//
+// tr is typically the trace of a single channel, as returned by
+// Trace.Channel. Waits are measured by the Asserter's Clock if t is one, and
+// each send fails the test if it isn't quickly received.
+//
+// Replay returns when it's done; to use it as a producer running alongside
+// the test, call it from Go.
+func Replay[T any](t TestingT, tr Trace, ch chan<- T, speed float64) {
+	a := asserterFor(t)
+	a.t.Helper()
+
+	values := make([]T, len(tr))
+	for i, e := range tr {
+		if e.Kind != EventSend || e.Value == nil {
+			continue
+		}
+		v, ok := e.Value.(T)
+		if !ok {
+			a.t.Fatal(fmt.Sprintf("can't replay %s: value of type %T, want %T", e, e.Value, v))
+			return
+		}
+		values[i] = v
+	}
+
+	start := a.clock.Now()
+	for i, e := range tr {
+		if e.Kind == EventRecv {
+			continue
+		}
+		if speed > 0 {
+			at := start.Add(time.Duration(float64(e.Time.Sub(tr[0].Time)) / speed))
+			if wait := at.Sub(a.clock.Now()); wait > 0 {
+				c, stop := startTimer(a.clock, wait)
+				<-c
+				stop()
+			}
+		}
+		if e.Kind == EventClose {
+			close(ch)
+			return
+		}
+		if !send(a, ch, values[i]) {
+			a.t.Fatal(fmt.Sprintf("timeout replaying %s", e))
+			return
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplay(t *testing.T) {
+	start := time.Unix(0, 0)
+	tr := Trace{
+		{Seq: 0, Channel: "c", Kind: EventSend, Value: 1, Time: start},
+		{Seq: 1, Channel: "c", Kind: EventRecv, Value: 1, Time: start.Add(time.Second)},
+		{Seq: 2, Channel: "c", Kind: EventSend, Value: 2, Time: start.Add(10 * time.Second)},
+		{Seq: 3, Channel: "c", Kind: EventClose, Time: start.Add(20 * time.Second)},
+	}
+
+	clock := NewFakeClock(start)
+	a := New(t, WithClock(clock))
+	ch := make(chan int)
+	g := Go(a, func(a *Asserter) { Replay(a, tr, ch, 2) })
+
+	Recv(a, ch)
+	clock.BlockUntil(1)
+	NoRecv(New(t, short), ch)
+	clock.Advance(5 * time.Second)
+	if got := Recv(a, ch); got != 2 {
+		t.Fatalf("got %d, want 2", got)
+	}
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Second)
+	if _, ok := <-ch; ok {
+		t.Fatal("channel not closed")
+	}
+	g.Wait()
+}
+
+func TestReplayUntimed(t *testing.T) {
+	tr := Trace{
+		{Kind: EventSend, Value: "a", Time: time.Unix(0, 0)},
+		{Kind: EventSend, Value: "b", Time: time.Unix(3600, 0)},
+	}
+	ch := make(chan string, 2)
+	Replay(t, tr, ch, 0)
+	if a, b := <-ch, <-ch; a != "a" || b != "b" {
+		t.Fatalf("got %q, %q", a, b)
+	}
+}
+
+func TestReplayFailures(t *testing.T) {
+	tr := Trace{{Channel: "c", Kind: EventSend, Value: "a"}}
+	assertFails(t, "value of type string, want int", func(t TestingT) {
+		Replay(t, tr, make(chan int, 1), 0)
+	})
+	assertFails(t, "timeout replaying #0 c send a", func(t TestingT) {
+		Replay(New(t, short), tr, make(chan string), 0)
+	})
+}
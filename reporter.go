@@ -0,0 +1,40 @@
+package chantest
+
+import "fmt"
+
+// A TestReporter is a failure sink in the style of gomock's TestReporter,
+// such as a gomock Controller's T.
+type TestReporter interface {
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Reporter returns a TestingT reporting failures to r, so that channel
+// assertion failures end up where mock expectation failures do. Fatal calls
+// r.Fatalf, and, for failures reported from other goroutines, Error calls
+// r.Errorf.
+//
+// Helper is forwarded to r if it has such a method.
+func Reporter(r TestReporter) TestingT {
+	return reporterT{r}
+}
+
+type reporterT struct {
+	r TestReporter
+}
+
+func (t reporterT) Helper() {
+	if h, ok := t.r.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+}
+
+func (t reporterT) Fatal(args ...interface{}) {
+	t.Helper()
+	t.r.Fatalf("%s", fmt.Sprint(args...))
+}
+
+func (t reporterT) Error(args ...interface{}) {
+	t.Helper()
+	t.r.Errorf("%s", fmt.Sprint(args...))
+}
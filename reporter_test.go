@@ -0,0 +1,49 @@
+package chantest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// mockReporter is a TestReporter like a gomock Controller's T, recording what
+// it's given.
+type mockReporter struct {
+	mu     sync.Mutex
+	errors []string
+	fatals []string
+}
+
+func (r *mockReporter) Errorf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *mockReporter) Fatalf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fatals = append(r.fatals, fmt.Sprintf(format, args...))
+}
+
+func TestReporterFailures(t *testing.T) {
+	r := &mockReporter{}
+	rt := Reporter(r)
+
+	short.AssertRecv(rt, make(chan int), "nothing from %s", "ch")
+	if len(r.fatals) != 1 || r.fatals[0] != "nothing from ch" {
+		t.Fatalf("unexpected Fatalf calls: %q", r.fatals)
+	}
+
+	c := Instrument(make(chan int, 2))
+	c.Limit(rt, 1)
+	c.In() <- 1
+	c.In() <- 2
+	c.AssertSendCount(t, 2)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.errors) != 1 || r.errors[0] != "2 values in flight, over the limit of 1" {
+		t.Fatalf("unexpected Errorf calls: %q", r.errors)
+	}
+}
@@ -0,0 +1,48 @@
+package chantest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Retry makes an Asserter's AssertRecv, AssertSend, Recv and Send try again,
+// up to attempts times in all, when they time out, for channels fed by
+// external systems, like the network or a subprocess, that can take longer
+// than usual now and then. Each retry waits backoff longer than the one
+// before, doubling: the Before duration, then plus backoff, plus twice
+// backoff, plus four times, and so on. The failure lists how long each
+// attempt waited.
+//
+// Negative assertions, like AssertNoRecv, don't retry, as waiting longer only
+// makes them stricter.
+func Retry(attempts int, backoff time.Duration) Option {
+	return optionFunc(func(c *config) { c.retry = &retryPolicy{attempts: attempts, backoff: backoff} })
+}
+
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// retry calls try, after a first attempt with the Asserter that timed out, for
+// each retry set by Retry, with longer Before durations, until it succeeds. It
+// returns try's results and, if they all fail, a description of the attempts
+// to append to the failure message.
+func retry[T any](a *Asserter, try func(a *Asserter) (T, bool)) (v T, ok bool, attempts string) {
+	if a.retry == nil || a.retry.attempts <= 1 {
+		return v, false, ""
+	}
+	waits := []string{time.Duration(a.d).String()}
+	c := a.config
+	extra := a.retry.backoff
+	for i := 1; i < a.retry.attempts; i++ {
+		c.d = a.d + Before(extra)
+		if v, ok = try(&Asserter{t: a.t, config: c}); ok {
+			return v, true, ""
+		}
+		waits = append(waits, time.Duration(c.d).String())
+		extra *= 2
+	}
+	return v, false, fmt.Sprintf("; %d attempts timed out after %s", len(waits), strings.Join(waits, ", "))
+}
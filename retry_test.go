@@ -0,0 +1,33 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	a := New(t, Before(5*time.Millisecond), Retry(4, 20*time.Millisecond))
+	ch := make(chan int)
+	go func() {
+		time.Sleep(15 * time.Millisecond)
+		ch <- 1
+	}()
+	if got := Recv(a, ch); got != 1 {
+		t.Fatalf("got %v", got)
+	}
+	go func() {
+		time.Sleep(15 * time.Millisecond)
+		<-ch
+	}()
+	a.AssertSend(ch, 2)
+
+	assertFails(t, "timeout waiting for channel send or receive; 3 attempts timed out after 1ms, 3ms, 5ms", func(t TestingT) {
+		AssertRecv(New(t, Before(time.Millisecond), Retry(3, 2*time.Millisecond)), ch)
+	})
+	assertFails(t, "no ack; 2 attempts timed out after 1ms, 2ms", func(t TestingT) {
+		Send(New(t, Before(time.Millisecond), Retry(2, time.Millisecond)), ch, 1, "no ack")
+	})
+	assertPasses(t, func(t TestingT) {
+		NoRecv(New(t, Before(time.Millisecond), Retry(3, time.Hour)), ch)
+	})
+}
@@ -0,0 +1,32 @@
+package chantest
+
+import "fmt"
+
+// AssertRoundTrip asserts that req is sent to reqCh, and then a response
+// received from respCh, all quickly, and returns the response. Both legs share
+// a single timeout, and the failure says which one stalled.
+func AssertRoundTrip[Req, Resp any](t TestingT, reqCh chan<- Req, req Req, respCh <-chan Resp, msgAndArgs ...interface{}) Resp {
+	a := asserterFor(t)
+	a.t.Helper()
+	tm := a.startTimer()
+	defer tm.stop()
+	var resp Resp
+	select {
+	case reqCh <- req:
+	case <-tm.C:
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("timeout sending request %#v", req), msgAndArgs...))
+		return resp
+	}
+	select {
+	case resp, ok := <-respCh:
+		if !ok {
+			a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("response channel closed after sending request %#v", req), msgAndArgs...))
+			return resp
+		}
+		a.matched(respCh, resp)
+		return resp
+	case <-tm.C:
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("timeout waiting for response to request %#v", req), msgAndArgs...))
+		return resp
+	}
+}
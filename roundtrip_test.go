@@ -0,0 +1,30 @@
+package chantest
+
+import "testing"
+
+func TestAssertRoundTrip(t *testing.T) {
+	reqs, resps := make(chan int), make(chan string)
+	go func() {
+		for req := range reqs {
+			if req < 0 {
+				continue
+			}
+			resps <- "ok"
+		}
+		close(resps)
+	}()
+
+	if got := AssertRoundTrip(t, reqs, 1, resps); got != "ok" {
+		t.Fatalf("got %q", got)
+	}
+	assertFails(t, "timeout waiting for response to request -1", func(t TestingT) {
+		AssertRoundTrip(New(t, short), reqs, -1, resps)
+	})
+	close(reqs)
+	assertFails(t, "response channel closed after sending request", func(t TestingT) {
+		AssertRoundTrip(New(t, short), make(chan int, 1), 2, resps)
+	})
+	assertFails(t, "timeout sending request 3", func(t TestingT) {
+		AssertRoundTrip(New(t, short), make(chan int), 3, resps)
+	})
+}
@@ -0,0 +1,146 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// A Case is a case of a select statement built at runtime, receiving from
+// Chan, or, if Send is set, sending Value to it. Chan must be a channel.
+type Case struct {
+	Chan  interface{}
+	Send  bool
+	Value interface{}
+}
+
+// Cases are the cases of a select statement built at runtime, identified by
+// their index.
+type Cases []Case
+
+// TimeoutCase is the index AssertSelect takes for the timeout firing before
+// any of the cases.
+const TimeoutCase = -1
+
+func (cs Cases) reflect() []reflect.SelectCase {
+	cases := make([]reflect.SelectCase, len(cs))
+	for i, c := range cs {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.Chan)}
+		if c.Send {
+			cases[i].Dir, cases[i].Send = reflect.SelectSend, reflect.ValueOf(c.Value)
+		}
+	}
+	return cases
+}
+
+// AssertSelect calls Asserter.AssertSelect on New(t).
+func AssertSelect(t TestingT, cases Cases, want int, msgAndArgs ...interface{}) interface{} {
+	a := asserterFor(t)
+	a.t.Helper()
+	return a.AssertSelect(cases, want, msgAndArgs...)
+}
+
+// AssertSelect is Before.AssertSelect with the Asserter's configuration.
+func (a *Asserter) AssertSelect(cases Cases, want int, msgAndArgs ...interface{}) interface{} {
+	a.t.Helper()
+	return a.d.AssertSelect(a, cases, want, msgAndArgs...)
+}
+
+// AssertSelect asserts that, of cases, the one at index want quickly
+// proceeds, and returns the value received, if it's a receive. With want
+// TimeoutCase, it asserts that none of the cases proceed for a very short
+// period of time.
+func (d Before) AssertSelect(t TestingT, cases Cases, want int, msgAndArgs ...interface{}) interface{} {
+	a := d.on(t)
+	a.t.Helper()
+	i, v, _ := a.selectCases(cases.reflect())
+	if i == want {
+		return v
+	}
+	var msg string
+	switch {
+	case i == TimeoutCase:
+		msg = fmt.Sprintf("timeout waiting for select case %d", want)
+	case want == TimeoutCase:
+		msg = fmt.Sprintf("select case %d proceeded; want timeout", i)
+	default:
+		msg = fmt.Sprintf("select case %d proceeded; want case %d", i, want)
+	}
+	a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+	return v
+}
+
+// A Selection is a select statement built at runtime with Select.
+type Selection struct {
+	cases Cases
+	// within, if set, overrides the timeout.
+	within *Before
+}
+
+// Select returns an empty Selection, to add cases to.
+//
+//	i, v := chantest.Select().Recv(a).Recv(b).Send(c, v).Within(d).Require(t)
+func Select() *Selection {
+	return &Selection{}
+}
+
+// Recv adds a case receiving from ch, which must be a channel.
+func (s *Selection) Recv(ch interface{}) *Selection {
+	s.cases = append(s.cases, Case{Chan: ch})
+	return s
+}
+
+// Send adds a case sending v to ch, which must be a channel.
+func (s *Selection) Send(ch, v interface{}) *Selection {
+	s.cases = append(s.cases, Case{Chan: ch, Send: true, Value: v})
+	return s
+}
+
+// Cases returns the cases added so far.
+func (s *Selection) Cases() Cases {
+	return s.cases
+}
+
+// Within makes Require wait for d, instead of t's timeout.
+func (s *Selection) Within(d time.Duration) *Selection {
+	within := Before(d)
+	s.within = &within
+	return s
+}
+
+// Require asserts that one of the cases quickly proceeds, and returns its
+// index, in the order the cases were added, and, for a receive, the value
+// received.
+func (s *Selection) Require(t TestingT, msgAndArgs ...interface{}) (index int, v interface{}) {
+	a := asserterFor(t)
+	if s.within != nil {
+		a = s.within.on(a)
+	}
+	a.t.Helper()
+	i, v, ok := a.selectCases(s.cases.reflect())
+	if !ok {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("timeout waiting for any of %d select cases", len(s.cases)), msgAndArgs...))
+		return TimeoutCase, nil
+	}
+	return i, v
+}
+
+// selectCases runs a select with cases and a timeout case, and returns the
+// index of the chosen case, or TimeoutCase, and, for a receive, the value
+// received. ok is false on timeout.
+func (a *Asserter) selectCases(cases []reflect.SelectCase) (index int, v interface{}, ok bool) {
+	timeout, stop := a.timeout()
+	defer stop()
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)})
+	chosen, recv, recvOK := reflect.Select(cases)
+	if chosen == len(cases)-1 {
+		return TimeoutCase, nil, false
+	}
+	if cases[chosen].Dir == reflect.SelectSend {
+		return chosen, nil, true
+	}
+	if recvOK {
+		a.matched(cases[chosen].Chan.Interface(), recv.Interface())
+	}
+	return chosen, recv.Interface(), true
+}
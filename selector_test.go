@@ -0,0 +1,45 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelect(t *testing.T) {
+	a, b, c := make(chan int), make(chan string, 1), make(chan int, 1)
+	b <- "b"
+	if i, v := Select().Recv(a).Recv(b).Require(t); i != 1 || v != "b" {
+		t.Fatalf("got %d, %v", i, v)
+	}
+	if i, v := Select().Recv(a).Send(c, 3).Require(t); i != 1 || v != nil {
+		t.Fatalf("got %d, %v", i, v)
+	}
+	if got := <-c; got != 3 {
+		t.Fatalf("sent %d, want 3", got)
+	}
+
+	assertFails(t, "timeout waiting for any of 2 select cases", func(t TestingT) {
+		Select().Recv(a).Send(make(chan int), 1).Within(10 * time.Millisecond).Require(t)
+	})
+}
+
+func TestAssertSelect(t *testing.T) {
+	a, b := make(chan int, 1), make(chan int, 1)
+	b <- 2
+	cases := Cases{{Chan: a}, {Chan: b}}
+	if v := AssertSelect(t, cases, 1); v != 2 {
+		t.Fatalf("got %v, want 2", v)
+	}
+	AssertSelect(New(t, short), cases, TimeoutCase)
+
+	a <- 1
+	assertFails(t, "select case 0 proceeded; want case 1", func(t TestingT) {
+		AssertSelect(t, cases, 1)
+	})
+	assertFails(t, "select case 0 proceeded; want timeout", func(t TestingT) {
+		AssertSelect(t, Cases{{Chan: a, Send: true, Value: 1}}, TimeoutCase)
+	})
+	assertFails(t, "timeout waiting for select case 1", func(t TestingT) {
+		short.AssertSelect(t, Select().Recv(b).Recv(make(chan int)).Cases(), 1)
+	})
+}
@@ -0,0 +1,70 @@
+package chantest
+
+import "fmt"
+
+// The functions in this file are for semaphores made of a buffered
+// chan struct{} holding the available tokens: acquiring receives a token, and
+// releasing sends it back.
+
+// SeedTokens sends n tokens to sem, failing the test if its buffer doesn't
+// have room for them.
+func SeedTokens(t TestingT, sem chan<- struct{}, n int) {
+	t = unwrap(t)
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		default:
+			t.Fatal(fmt.Sprintf("no room for token %d of %d; semaphore has %d of %d", i+1, n, len(sem), cap(sem)))
+			return
+		}
+	}
+}
+
+// DrainTokens receives the tokens available in sem without waiting, and
+// returns how many there were.
+func DrainTokens(sem <-chan struct{}) int {
+	n := 0
+	for {
+		select {
+		case <-sem:
+			n++
+		default:
+			return n
+		}
+	}
+}
+
+// AssertTokensAvailable asserts that the number of tokens in sem very quickly
+// is, or gets to, n.
+func AssertTokensAvailable(t TestingT, sem chan struct{}, n int, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	if !a.poll(func() bool { return len(sem) == n }) {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("%d tokens available, want %d", len(sem), n), msgAndArgs...))
+	}
+}
+
+// AssertAcquireBlocks asserts that no token can be acquired from sem for a
+// very short period of time. If one is, it's released back, waiting for the
+// Asserter's timeout at most, before failing. It also fails if sem is closed.
+func AssertAcquireBlocks(t TestingT, sem chan struct{}, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	_, ok, received := recvOrClose(a, sem)
+	if !received {
+		return
+	}
+	if !ok {
+		a.t.Fatal(defaultOrCustomMessage("semaphore closed", msgAndArgs...))
+		return
+	}
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case sem <- struct{}{}:
+		a.t.Fatal(defaultOrCustomMessage("unexpectedly acquired a token", msgAndArgs...))
+	case <-timeout:
+		a.t.Fatal(defaultOrCustomMessage("unexpectedly acquired a token, and timed out releasing it back", msgAndArgs...))
+	}
+}
@@ -0,0 +1,48 @@
+package chantest
+
+import "testing"
+
+func TestSemaphore(t *testing.T) {
+	sem := make(chan struct{}, 2)
+	SeedTokens(t, sem, 2)
+	AssertTokensAvailable(t, sem, 2)
+	assertFails(t, "no room for token 1 of 1; semaphore has 2 of 2", func(t TestingT) {
+		SeedTokens(t, sem, 1)
+	})
+
+	<-sem
+	AssertTokensAvailable(t, sem, 1)
+	go func() { <-sem }()
+	AssertTokensAvailable(t, sem, 0)
+	AssertAcquireBlocks(New(t, short), sem)
+
+	go func() { sem <- struct{}{} }()
+	assertFails(t, "unexpectedly acquired a token", func(t TestingT) {
+		AssertAcquireBlocks(t, sem)
+	})
+	assertFails(t, "1 tokens available, want 2", func(t TestingT) {
+		AssertTokensAvailable(New(t, short), sem, 2)
+	})
+	if n := DrainTokens(sem); n != 1 {
+		t.Fatalf("drained %d tokens, want 1", n)
+	}
+
+	// A blocked release takes the acquired token's place.
+	full := make(chan struct{}, 1)
+	full <- struct{}{}
+	go releaseToken(full)
+	AssertBlockedOn(t, OpSend, releaseToken)
+	assertFails(t, "unexpectedly acquired a token, and timed out releasing it back", func(t TestingT) {
+		AssertAcquireBlocks(New(t, short), full)
+	})
+
+	closed := make(chan struct{}, 1)
+	close(closed)
+	assertFails(t, "semaphore closed", func(t TestingT) {
+		AssertAcquireBlocks(t, closed)
+	})
+}
+
+func releaseToken(sem chan struct{}) {
+	sem <- struct{}{}
+}
@@ -0,0 +1,75 @@
+package chantest
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A Sequencer asserts that named steps, typically reached by different
+// goroutines, happen in a declared order.
+type Sequencer struct {
+	want []string
+
+	mu  sync.Mutex
+	got []string
+	// violation, if set, describes the first unexpected step.
+	violation string
+	// done is closed once all steps are observed, or one is out of order.
+	done chan struct{}
+}
+
+// NewSequencer returns a Sequencer that expects steps in the given order. A
+// step name may appear more than once.
+func NewSequencer(steps ...string) *Sequencer {
+	s := &Sequencer{want: steps, done: make(chan struct{})}
+	if len(steps) == 0 {
+		close(s.done)
+	}
+	return s
+}
+
+// Step records that the named step is reached. It can be called from any
+// goroutine; a step that isn't the next expected one, or comes after the
+// last, is reported by AssertDone.
+func (s *Sequencer) Step(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.violation != "" {
+		return
+	}
+
+	s.got = append(s.got, name)
+	n := len(s.got)
+	switch {
+	case n > len(s.want):
+		// done was closed when the last expected step was reached.
+		s.violation = fmt.Sprintf("unexpected step %q after the last; observed %q, expected %q", name, s.got, s.want)
+	case s.want[n-1] != name:
+		s.violation = fmt.Sprintf("unexpected step %q; observed %q, expected %q", name, s.got, s.want)
+		close(s.done)
+	case n == len(s.want):
+		close(s.done)
+	}
+}
+
+// AssertDone asserts that all expected steps are quickly reached, in order,
+// and no others.
+func (s *Sequencer) AssertDone(t TestingT, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case <-s.done:
+	case <-timeout:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.violation != "":
+		a.t.Fatal(defaultOrCustomMessage(s.violation, msgAndArgs...))
+	case len(s.got) < len(s.want):
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("timeout waiting for steps; observed %q, expected %q", s.got, s.want), msgAndArgs...))
+	}
+}
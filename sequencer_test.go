@@ -0,0 +1,58 @@
+package chantest
+
+import "testing"
+
+func TestSequencer(t *testing.T) {
+	assertPasses(t, func(t TestingT) {
+		s := NewSequencer("start", "work", "stop")
+		ch := make(chan struct{})
+		worked := make(chan struct{})
+		go func() {
+			<-ch
+			s.Step("work")
+			close(worked)
+		}()
+		s.Step("start")
+		AssertCloses(t, worked, func() { close(ch) })
+		s.Step("stop")
+		s.AssertDone(t)
+	})
+
+	assertFails(t, `unexpected step "stop"; observed ["start" "stop"], expected ["start" "work" "stop"]`, func(t TestingT) {
+		s := NewSequencer("start", "work", "stop")
+		s.Step("start")
+		s.Step("stop")
+		s.AssertDone(t)
+	})
+
+	assertFails(t, `unexpected step "work"; observed ["work"], expected ["start" "work"]`, func(t TestingT) {
+		s := NewSequencer("start", "work")
+		go s.Step("work")
+		s.AssertDone(t)
+	})
+
+	assertFails(t, `timeout waiting for steps; observed ["start"]`, func(t TestingT) {
+		s := NewSequencer("start", "work")
+		s.Step("start")
+		s.AssertDone(New(t, short))
+	})
+}
+
+func TestSequencerExtraStep(t *testing.T) {
+	assertFails(t, `unexpected step "again" after the last; observed ["start" "again"], expected ["start"]`, func(t TestingT) {
+		s := NewSequencer("start")
+		s.Step("start")
+		s.Step("again")
+		s.Step("more")
+		s.AssertDone(t)
+	})
+
+	assertPasses(t, func(t TestingT) {
+		NewSequencer().AssertDone(t)
+	})
+	assertFails(t, `unexpected step "start" after the last; observed ["start"], expected []`, func(t TestingT) {
+		s := NewSequencer()
+		s.Step("start")
+		s.AssertDone(t)
+	})
+}
@@ -0,0 +1,149 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AssertAllUnblockedOnClose starts each of waiters in a goroutine, asserts
+// that they stay blocked until done is closed, then closes done, and asserts
+// that they all return very quickly after that. The failure lists the waiters,
+// by index, that returned early or stayed blocked.
+//
+// Useful for checking that closing a channel to broadcast shutdown reaches
+// every waiter, and not just the ones listening on the right channel.
+func AssertAllUnblockedOnClose[T any](t TestingT, done chan T, waiters ...func()) {
+	a := asserterFor(t)
+	a.t.Helper()
+	returned := make(chan int, len(waiters))
+	for i, wait := range waiters {
+		i, wait := i, wait
+		go func() {
+			defer func() { returned <- i }()
+			wait()
+		}()
+	}
+
+	blocked, stop := a.timeout()
+	defer stop()
+	var early []int
+	for waiting := true; waiting; {
+		select {
+		case i := <-returned:
+			early = append(early, i)
+		case <-blocked:
+			waiting = false
+		}
+	}
+	if len(early) > 0 {
+		a.t.Fatal(fmt.Sprintf("waiters %v returned before close", early))
+		return
+	}
+
+	close(done)
+
+	timeout, stop := a.timeout()
+	defer stop()
+	released := make([]bool, len(waiters))
+	for n := 0; n < len(waiters); n++ {
+		select {
+		case i := <-returned:
+			released[i] = true
+		case <-timeout:
+			var stuck []int
+			for i, ok := range released {
+				if !ok {
+					stuck = append(stuck, i)
+				}
+			}
+			a.t.Fatal(fmt.Sprintf("waiters %v still blocked after close", stuck))
+			return
+		}
+	}
+}
+
+// A Shutdown is a harness for a graceful shutdown: it triggers it, and then
+// asserts that everything expected to happen does, within a single timeout.
+type Shutdown struct {
+	trigger func()
+	checks  []shutdownCheck
+}
+
+type shutdownCheck struct {
+	name string
+	ch   reflect.Value
+	// closes is whether ch must get closed, rather than just fire.
+	closes bool
+}
+
+// NewShutdown returns a Shutdown triggered by trigger, such as a context's
+// cancel function or a func closing a quit channel.
+func NewShutdown(trigger func()) *Shutdown {
+	return &Shutdown{trigger: trigger}
+}
+
+// ExpectClosed expects ch, which must be a channel, to be closed, without any
+// further values sent to it. It returns s.
+func (s *Shutdown) ExpectClosed(name string, ch interface{}) *Shutdown {
+	s.checks = append(s.checks, shutdownCheck{name: name, ch: reflect.ValueOf(ch), closes: true})
+	return s
+}
+
+// ExpectFired expects a receive from ch, which must be a channel, to happen,
+// either by a value being sent or ch being closed, as with a done channel. It
+// returns s.
+func (s *Shutdown) ExpectFired(name string, ch interface{}) *Shutdown {
+	s.checks = append(s.checks, shutdownCheck{name: name, ch: reflect.ValueOf(ch)})
+	return s
+}
+
+// Run triggers the shutdown and waits for the expected channels, failing the
+// test with a report of every expectation not met before the timeout, and of
+// values sent to channels expected to be closed.
+func (s *Shutdown) Run(t TestingT, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	s.trigger()
+
+	timeout, stop := a.timeout()
+	defer stop()
+	met := make([]bool, len(s.checks))
+	emitted := make([][]interface{}, len(s.checks))
+	cases := make([]reflect.SelectCase, len(s.checks)+1)
+	for i, c := range s.checks {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: c.ch}
+	}
+	cases[len(s.checks)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)}
+	for pending := len(s.checks); pending > 0; {
+		chosen, v, ok := reflect.Select(cases)
+		if chosen == len(s.checks) {
+			break
+		}
+		if ok && s.checks[chosen].closes {
+			emitted[chosen] = append(emitted[chosen], v.Interface())
+			continue
+		}
+		met[chosen] = true
+		// reflect.Select ignores cases with a zero Chan.
+		cases[chosen].Chan = reflect.Value{}
+		pending--
+	}
+
+	var problems []string
+	for i, c := range s.checks {
+		if len(emitted[i]) > 0 {
+			problems = append(problems, fmt.Sprintf("%s: sent %v after shutdown", c.name, emitted[i]))
+		}
+		switch {
+		case met[i]:
+		case c.closes:
+			problems = append(problems, c.name+": not closed")
+		default:
+			problems = append(problems, c.name+": not fired")
+		}
+	}
+	if len(problems) > 0 {
+		a.t.Fatal(defaultOrCustomMessage("shutdown incomplete: "+strings.Join(problems, "; "), msgAndArgs...))
+	}
+}
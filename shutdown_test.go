@@ -0,0 +1,64 @@
+package chantest
+
+import "testing"
+
+func TestAssertAllUnblockedOnClose(t *testing.T) {
+	assertPasses(t, func(t TestingT) {
+		done := make(chan struct{})
+		AssertAllUnblockedOnClose(New(t, short), done,
+			func() { <-done },
+			func() {
+				select {
+				case <-done:
+				case <-make(chan int):
+				}
+			},
+		)
+	})
+	assertFails(t, "waiters [1] still blocked after close", func(t TestingT) {
+		done, wrong := make(chan struct{}), make(chan struct{})
+		AssertAllUnblockedOnClose(New(t, short), done,
+			func() { <-done },
+			func() { <-wrong },
+		)
+	})
+	assertFails(t, "waiters [0] returned before close", func(t TestingT) {
+		AssertAllUnblockedOnClose(New(t, short), make(chan int), func() {})
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	// start runs a component that sends to out until quit is closed, then
+	// closes out and, unless leaky, done.
+	start := func(leaky bool) (quit, done chan struct{}, out chan int) {
+		quit, done, out = make(chan struct{}), make(chan struct{}), make(chan int)
+		go func() {
+			defer close(out)
+			<-quit
+			if leaky {
+				out <- 1
+				return
+			}
+			close(done)
+		}()
+		return quit, done, out
+	}
+
+	assertPasses(t, func(t TestingT) {
+		quit, done, out := start(false)
+		NewShutdown(func() { close(quit) }).
+			ExpectClosed("out", out).
+			ExpectFired("done", done).
+			Run(t)
+	})
+	assertFails(t, "shutdown incomplete: out: sent [1] after shutdown; done: not fired", func(t TestingT) {
+		quit, done, out := start(true)
+		NewShutdown(func() { close(quit) }).
+			ExpectClosed("out", out).
+			ExpectFired("done", done).
+			Run(New(t, short))
+	})
+	assertFails(t, "shutdown incomplete: out: not closed", func(t TestingT) {
+		NewShutdown(func() {}).ExpectClosed("out", make(chan int)).Run(New(t, short))
+	})
+}
@@ -0,0 +1,65 @@
+package chantest
+
+import "sync"
+
+// A Signal is a one-shot event, like the commonly used chan struct{} that is
+// closed to notify waiters, with assertions attached.
+type Signal struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+// NewSignal returns a Signal that hasn't fired.
+func NewSignal() *Signal {
+	return &Signal{ch: make(chan struct{})}
+}
+
+// Fire fires the signal, unblocking all current and future receivers from
+// Done. Calls after the first have no effect.
+func (s *Signal) Fire() {
+	s.once.Do(func() { close(s.ch) })
+}
+
+// Done returns a channel that is closed when the signal fires, for the code
+// under test to select on.
+func (s *Signal) Done() <-chan struct{} {
+	return s.ch
+}
+
+// Fired reports whether the signal has fired.
+func (s *Signal) Fired() bool {
+	select {
+	case <-s.ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// AssertFired asserts that the signal fires very quickly, if it hasn't
+// already.
+func (s *Signal) AssertFired(t TestingT, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case <-s.ch:
+	case <-timeout:
+		a.t.Fatal(defaultOrCustomMessage("timeout waiting for signal to fire", msgAndArgs...))
+	}
+}
+
+// AssertNotFired asserts that the signal doesn't fire for a very short period
+// of time.
+func (s *Signal) AssertNotFired(t TestingT, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	select {
+	case <-s.ch:
+		a.t.Fatal(defaultOrCustomMessage("unexpected signal fire", msgAndArgs...))
+	case <-timeout:
+	}
+}
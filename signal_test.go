@@ -0,0 +1,26 @@
+package chantest
+
+import "testing"
+
+func TestSignal(t *testing.T) {
+	s := NewSignal()
+	assertPasses(t, func(t TestingT) {
+		s.AssertNotFired(New(t, short))
+	})
+	assertFails(t, "timeout waiting for signal to fire", func(t TestingT) {
+		s.AssertFired(New(t, short))
+	})
+	if s.Fired() {
+		t.Fatal("signal fired before Fire")
+	}
+
+	go s.Fire()
+	assertPasses(t, func(t TestingT) {
+		s.AssertFired(t)
+		AssertRecv(t, s.Done())
+	})
+	s.Fire()
+	assertFails(t, "unexpected signal fire", func(t TestingT) {
+		s.AssertNotFired(t)
+	})
+}
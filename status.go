@@ -0,0 +1,93 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A Status is the outcome of waiting to receive from a channel.
+type Status int
+
+const (
+	// TimedOut means nothing was received before the timeout.
+	TimedOut Status = iota
+	// Received means a value was received, even if it's nil or zero.
+	Received
+	// Closed means the channel was closed, so the zero value was received.
+	Closed
+)
+
+func (s Status) String() string {
+	switch s {
+	case TimedOut:
+		return "timed out"
+	case Received:
+		return "received"
+	case Closed:
+		return "closed"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// RecvOrClose waits to receive from ch, which must be a channel, and returns
+// the value and whether it was sent, the channel was closed, or the wait timed
+// out. Unlike AssertRecv, it doesn't fail, and it tells a nil value sent on a
+// chan interface{} apart from the channel being closed.
+func RecvOrClose(t TestingT, ch interface{}) (v interface{}, status Status) {
+	a := asserterFor(t)
+	a.t.Helper()
+	return a.recvStatus(ch)
+}
+
+// AssertRecvStatus asserts that waiting to receive from ch, which must be a
+// channel, ends as set by want, and returns the value received, if any.
+func AssertRecvStatus(t TestingT, ch interface{}, want Status, msgAndArgs ...interface{}) interface{} {
+	a := asserterFor(t)
+	a.t.Helper()
+	v, got := a.recvStatus(ch)
+	if got == want {
+		return v
+	}
+	var msg string
+	switch got {
+	case TimedOut:
+		msg = "timeout waiting for " + want.expected()
+	case Received:
+		msg = fmt.Sprintf("received %#v; expected %s", v, want.expected())
+	case Closed:
+		msg = "channel closed; expected " + want.expected()
+	}
+	a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+	return v
+}
+
+// expected describes what a wait that ends with s is waiting for.
+func (s Status) expected() string {
+	switch s {
+	case TimedOut:
+		return "nothing"
+	case Received:
+		return "a value"
+	}
+	return "channel to be closed"
+}
+
+func (a *Asserter) recvStatus(ch interface{}) (interface{}, Status) {
+	timeout, stop := a.timeout()
+	defer stop()
+	chosen, recv, recvOK := reflect.Select([]reflect.SelectCase{{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(ch),
+	}, {
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(timeout),
+	}})
+	switch {
+	case chosen != 0:
+		return nil, TimedOut
+	case !recvOK:
+		return recv.Interface(), Closed
+	}
+	a.matched(ch, recv.Interface())
+	return recv.Interface(), Received
+}
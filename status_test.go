@@ -0,0 +1,38 @@
+package chantest
+
+import "testing"
+
+func TestRecvOrClose(t *testing.T) {
+	ch := make(chan interface{}, 1)
+	ch <- nil
+	if v, status := RecvOrClose(t, ch); v != nil || status != Received {
+		t.Fatalf("got %v, %v", v, status)
+	}
+	if v, status := RecvOrClose(New(t, short), ch); v != nil || status != TimedOut {
+		t.Fatalf("got %v, %v", v, status)
+	}
+	close(ch)
+	if v, status := RecvOrClose(t, ch); v != nil || status != Closed {
+		t.Fatalf("got %v, %v", v, status)
+	}
+}
+
+func TestAssertRecvStatus(t *testing.T) {
+	ch := make(chan interface{}, 1)
+	ch <- nil
+	AssertRecvStatus(t, ch, Received)
+	AssertRecvStatus(New(t, short), ch, TimedOut)
+
+	ch <- nil
+	assertFails(t, "received <nil>; expected channel to be closed", func(t TestingT) {
+		AssertRecvStatus(t, ch, Closed)
+	})
+	assertFails(t, "timeout waiting for a value", func(t TestingT) {
+		AssertRecvStatus(New(t, short), ch, Received)
+	})
+	close(ch)
+	assertFails(t, "channel closed; expected a value", func(t TestingT) {
+		AssertRecvStatus(t, ch, Received)
+	})
+	AssertRecvStatus(t, ch, Closed)
+}
@@ -0,0 +1,20 @@
+package chantest
+
+import "testing"
+
+// Step runs fn as a subtest of t named name, with an Asserter for the subtest
+// that has a's configuration, and reports whether it passed, like t.Run.
+// Breaking a long scenario into steps attributes its failures to a named
+// phase rather than just a line.
+//
+//	if !a.Step(t, "worker drains queue", func(t *testing.T, a *chantest.Asserter) {
+//		...
+//	}) {
+//		return
+//	}
+func (a *Asserter) Step(t *testing.T, name string, fn func(t *testing.T, a *Asserter)) bool {
+	t.Helper()
+	return t.Run(name, func(t *testing.T) {
+		fn(t, New(t, a))
+	})
+}
@@ -0,0 +1,29 @@
+package chantest
+
+import "testing"
+
+func TestStep(t *testing.T) {
+	a := New(t, short, WithSeed(7))
+	ch := make(chan int, 1)
+	var names []string
+	for _, name := range []string{"send", "recv"} {
+		ok := a.Step(t, name, func(t *testing.T, sa *Asserter) {
+			names = append(names, t.Name())
+			if sa.d != short || sa.Seed() != 7 {
+				t.Fatalf("step Asserter has Before %v and seed %d", sa.d, sa.Seed())
+			}
+			switch name {
+			case "send":
+				sa.AssertSend(ch, 1)
+			case "recv":
+				sa.AssertRecv(ch)
+			}
+		})
+		if !ok {
+			t.Fatalf("step %s failed", name)
+		}
+	}
+	if len(names) != 2 || names[0] != "TestStep/send" || names[1] != "TestStep/recv" {
+		t.Fatalf("ran steps %v", names)
+	}
+}
@@ -0,0 +1,141 @@
+package chantest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// StressOptions configures Stress.
+type StressOptions struct {
+	// N is how many times to run the body. If neither N nor Duration is set,
+	// it's 100.
+	N int
+	// Duration, if set, keeps running the body until it elapses, or until N
+	// runs, if also set.
+	Duration time.Duration
+	// Parallel is how many runs to do at once. The default is 1.
+	Parallel int
+	// Seed is the seed of the first run; each following run's is one more.
+	// The default is the current time. To reproduce a failure, run once with
+	// the reported seed.
+	Seed int64
+	// Options configure each run's Asserter, which also gets the run's seed
+	// with WithSeed.
+	Options []Option
+}
+
+// Stress runs body repeatedly as subtests of t, as set by opts, to flush out
+// bugs that only show up in some interleavings. Runs go on after failures;
+// then Stress fails t with how many runs failed, and the iteration and seed of
+// the first one.
+//
+// If opts.Options include Perturb, the first failed run is retried with the
+// same seed and less perturbation: none, then 1, 2, 4 and so on, and the least
+// that still fails is reported, for a simpler reproduction.
+//
+//	chantest.Stress(t, chantest.StressOptions{N: 1000, Parallel: 8}, func(t *testing.T, a *chantest.Asserter) {
+//		...
+//	})
+func Stress(t *testing.T, opts StressOptions, body func(t *testing.T, a *Asserter)) {
+	t.Helper()
+	if opts.N == 0 && opts.Duration == 0 {
+		opts.N = 100
+	}
+	if opts.Parallel < 1 {
+		opts.Parallel = 1
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	run := func(name string, seed int64, extra ...Option) bool {
+		options := append(opts.Options[:len(opts.Options):len(opts.Options)], WithSeed(seed))
+		return t.Run(name, func(t *testing.T) {
+			body(t, New(t, append(options, extra...)...))
+		})
+	}
+	r := stress(opts, func(i int, seed int64) bool {
+		return run(fmt.Sprintf("#%d", i), seed)
+	})
+	if r.failed == 0 {
+		return
+	}
+	msg := fmt.Sprintf("%d of %d runs failed; first at iteration %d with seed %d", r.failed, r.runs, r.first, r.firstSeed)
+	cfg := defaultConfig()
+	for _, opt := range opts.Options {
+		opt.apply(&cfg)
+	}
+	if p, ok := shrinkPerturb(cfg.perturb, func(p int) bool {
+		return !run(fmt.Sprintf("#%d/Perturb(%d)", r.first, p), r.firstSeed, Perturb(p))
+	}); ok {
+		msg += fmt.Sprintf(", which also fails with Perturb(%d)", p)
+	}
+	t.Fatal(msg)
+}
+
+// shrinkPerturb returns the least of 0, 1, 2, 4 and so on, below max, for
+// which fails returns true.
+func shrinkPerturb(max int, fails func(p int) bool) (int, bool) {
+	for p := 0; p < max; {
+		if fails(p) {
+			return p, true
+		}
+		if p == 0 {
+			p = 1
+		} else {
+			p *= 2
+		}
+	}
+	return 0, false
+}
+
+type stressResult struct {
+	runs, failed int
+	// first is the first failed iteration, with firstSeed.
+	first     int
+	firstSeed int64
+}
+
+// stress calls run, which reports whether it passed, for each iteration as
+// set by opts, whose defaults are already applied.
+func stress(opts StressOptions, run func(i int, seed int64) bool) stressResult {
+	var deadline time.Time
+	if opts.Duration > 0 {
+		deadline = time.Now().Add(opts.Duration)
+	}
+
+	var mu sync.Mutex
+	r := stressResult{first: -1}
+	claim := func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if opts.N > 0 && r.runs >= opts.N || !deadline.IsZero() && !time.Now().Before(deadline) {
+			return 0, false
+		}
+		r.runs++
+		return r.runs - 1, true
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, ok := claim(); ok; i, ok = claim() {
+				seed := opts.Seed + int64(i)
+				if run(i, seed) {
+					continue
+				}
+				mu.Lock()
+				r.failed++
+				if r.first < 0 || i < r.first {
+					r.first, r.firstSeed = i, seed
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return r
+}
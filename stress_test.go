@@ -0,0 +1,81 @@
+package chantest
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStress(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		seeds = map[int64]bool{}
+	)
+	Stress(t, StressOptions{N: 20, Parallel: 4, Seed: 100}, func(t *testing.T, a *Asserter) {
+		mu.Lock()
+		seeds[a.Seed()] = true
+		mu.Unlock()
+
+		ch := make(chan int, 1)
+		go func() { ch <- a.Rand().Intn(10) }()
+		a.AssertRecv(ch)
+	})
+	if len(seeds) != 20 || !seeds[100] || !seeds[119] {
+		t.Fatalf("unexpected seeds: %v", seeds)
+	}
+}
+
+func TestStressResult(t *testing.T) {
+	r := stress(StressOptions{N: 50, Parallel: 3, Seed: 7}, func(i int, seed int64) bool {
+		return i%10 != 3
+	})
+	if r.runs != 50 || r.failed != 5 || r.first != 3 || r.firstSeed != 10 {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	start := time.Now()
+	r = stress(StressOptions{Duration: 20 * time.Millisecond, Parallel: 1, Seed: 1}, func(int, int64) bool {
+		time.Sleep(time.Millisecond)
+		return true
+	})
+	if r.runs == 0 || r.failed != 0 || time.Since(start) < 20*time.Millisecond {
+		t.Fatalf("unexpected result after %v: %+v", time.Since(start), r)
+	}
+}
+
+func TestRand(t *testing.T) {
+	a := New(t, WithSeed(42))
+	if a.Rand().Int63() != New(t, WithSeed(42)).Rand().Int63() {
+		t.Fatal("generators with the same seed differ")
+	}
+}
+
+func TestStressPerturb(t *testing.T) {
+	Stress(t, StressOptions{N: 10, Options: []Option{Perturb(5)}}, func(t *testing.T, a *Asserter) {
+		if a.perturb != 5 {
+			t.Fatalf("perturb is %d, want 5", a.perturb)
+		}
+		c := Instrument(make(chan int, 1), a)
+		if New(t, a).Seed() != a.Seed() {
+			t.Fatal("Asserter as Option didn't set the seed")
+		}
+		for i := 0; i < 5; i++ {
+			a.AssertSend(c.In(), i)
+			AssertRecvEqual(a, c.Out(), i)
+		}
+	})
+}
+
+func TestShrinkPerturb(t *testing.T) {
+	var tried []int
+	p, ok := shrinkPerturb(20, func(p int) bool {
+		tried = append(tried, p)
+		return p >= 3
+	})
+	if !ok || p != 4 || len(tried) != 4 {
+		t.Fatalf("shrunk to %d, %v, after trying %v", p, ok, tried)
+	}
+	if _, ok := shrinkPerturb(4, func(p int) bool { return p >= 4 }); ok {
+		t.Fatal("shrunk to the original perturbation")
+	}
+}
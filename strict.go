@@ -0,0 +1,58 @@
+package chantest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// matched records that an assertion received v from ch, if the Asserter is
+// Strict.
+func (a *Asserter) matched(ch, v interface{}) {
+	if !a.strict || a.registry == nil {
+		return
+	}
+	r := a.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.matched == nil {
+		r.matched = map[uintptr][]interface{}{}
+	}
+	p := reflect.ValueOf(ch).Pointer()
+	r.matched[p] = append(r.matched[p], v)
+}
+
+// Verify fails the test if a Strict Asserter's registered channels carried
+// values that its assertions didn't receive, listing them in the failure.
+// Values still in a channel are drained, waiting for the Asserter's timeout at
+// most, and listed too.
+//
+// Verify does nothing unless the Asserter is Strict.
+func (a *Asserter) Verify(msgAndArgs ...interface{}) {
+	a.t.Helper()
+	if !a.strict {
+		return
+	}
+	names, channels := a.registry.channelList()
+	var surplus []string
+	for i, c := range channels {
+		received := c.receivedValues()
+		pending := c.drain(a)
+		a.registry.mu.Lock()
+		matched := a.registry.matched[reflect.ValueOf(c.outChan()).Pointer()]
+		a.registry.mu.Unlock()
+		var problems []string
+		if _, outside := multisetDiff(a, matched, received); len(outside) > 0 {
+			problems = append(problems, fmt.Sprintf("received outside assertions %v", outside))
+		}
+		if len(pending) > 0 {
+			problems = append(problems, fmt.Sprintf("never received %v", pending))
+		}
+		if len(problems) > 0 {
+			surplus = append(surplus, fmt.Sprintf("%s: %s", names[i], strings.Join(problems, ", ")))
+		}
+	}
+	if len(surplus) > 0 {
+		a.t.Fatal(defaultOrCustomMessage("unasserted values on registered channels: "+strings.Join(surplus, "; "), msgAndArgs...))
+	}
+}
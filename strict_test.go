@@ -0,0 +1,54 @@
+package chantest
+
+import "testing"
+
+func TestStrict(t *testing.T) {
+	a := New(t, Strict())
+	orders := Instrument(make(chan string, 10))
+	acks := Instrument(make(chan int, 10))
+	a.Register("orders", orders)
+	a.Register("acks", acks)
+
+	orders.In() <- "pizza"
+	acks.In() <- 1
+	Recv(a, orders.Out())
+	a.AssertRecv(acks.Out())
+	assertPasses(t, func(t TestingT) {
+		rebind(t, a).Verify()
+	})
+
+	orders.In() <- "burger"
+	<-orders.Out()
+	orders.In() <- "salad"
+	acks.In() <- 2
+	orders.AssertLen(t, 1)
+	acks.AssertLen(t, 1)
+	assertFails(t, "unasserted values on registered channels: orders: received outside assertions [burger], never received [salad]; acks: never received [2]", func(t TestingT) {
+		rebind(t, a).Verify()
+	})
+}
+
+func TestVerifyNotStrict(t *testing.T) {
+	a := New(t)
+	ch := Instrument(make(chan int, 1))
+	a.Register("ch", ch)
+	ch.In() <- 1
+	assertPasses(t, func(t TestingT) {
+		rebind(t, a).Verify()
+	})
+}
+
+func TestVerifyRightAfterReceive(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		a := New(t, Strict())
+		ch := Instrument(make(chan int, 1))
+		a.Register("ch", ch)
+		ch.In() <- 1
+		ch.In() <- 2
+		Recv(a, ch.Out())
+		<-ch.Out()
+		assertFails(t, "unasserted values on registered channels: ch: received outside assertions [2]", func(t TestingT) {
+			rebind(t, a).Verify()
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package chantest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AssertSuccessRate runs body runs times in turn, and fails only if the share
+// of runs that pass, between 0 and 1, is below min. It's for properties that
+// only usually hold, like a fast path that usually wins a race, and so can't
+// be asserted on a single run. runs must be positive.
+//
+// Each run's failure, like with Go, is captured rather than reported. The
+// failure summarizes them by message, most frequent first.
+//
+// If t is an Asserter with a seed, as set with WithSeed, each run's Asserter
+// has that seed plus the run's index; otherwise the base seed is random.
+func AssertSuccessRate(t TestingT, runs int, min float64, body func(a *Asserter), msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	if runs <= 0 {
+		a.t.Fatal(fmt.Sprintf("runs must be positive, got %d", runs))
+		return
+	}
+	base := a.Seed()
+	if a.seed == nil {
+		base = time.Now().UnixNano()
+	}
+
+	failures := map[string]int{}
+	passed := 0
+	for i := 0; i < runs; i++ {
+		c := a.config
+		seed := base + int64(i)
+		c.seed = &seed
+		rec := &recordT{}
+		Go(&Asserter{t: rec, config: c}, body).Wait()
+		if rec.failure == nil {
+			passed++
+			continue
+		}
+		failures[*rec.failure]++
+	}
+
+	rate := float64(passed) / float64(runs)
+	if rate >= min {
+		return
+	}
+	msgs := make([]string, 0, len(failures))
+	for msg := range failures {
+		msgs = append(msgs, msg)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if failures[msgs[i]] != failures[msgs[j]] {
+			return failures[msgs[i]] > failures[msgs[j]]
+		}
+		return msgs[i] < msgs[j]
+	})
+	var summary []string
+	for _, msg := range msgs {
+		summary = append(summary, fmt.Sprintf("%d× %s", failures[msg], msg))
+	}
+	a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf(
+		"%d of %d runs passed, %.1f%%, want at least %.1f%%; failures:\n\t%s",
+		passed, runs, rate*100, min*100, strings.Join(summary, "\n\t"),
+	), msgAndArgs...))
+}
+
+// recordT is a TestingT that records the first failure.
+type recordT struct {
+	failure *string
+}
+
+func (t *recordT) Helper() {}
+
+func (t *recordT) Fatal(args ...interface{}) {
+	if t.failure == nil {
+		msg := fmt.Sprint(args...)
+		t.failure = &msg
+	}
+}
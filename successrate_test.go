@@ -0,0 +1,29 @@
+package chantest
+
+import "testing"
+
+func TestAssertSuccessRate(t *testing.T) {
+	flaky := func(a *Asserter) {
+		if n := a.Rand().Intn(10); n < 2 {
+			a.Fatal("unlucky")
+		} else if n < 3 {
+			a.Fatal("very unlucky")
+		}
+	}
+
+	assertPasses(t, func(t TestingT) {
+		AssertSuccessRate(New(t, WithSeed(1)), 100, 0.5, flaky)
+	})
+	assertFails(t, "× unlucky\n\t", func(t TestingT) {
+		AssertSuccessRate(New(t, WithSeed(1)), 100, 0.95, flaky)
+	})
+	assertFails(t, "0 of 3 runs passed, 0.0%, want at least 50.0%; failures:\n\t3× timeout waiting for channel send or receive", func(t TestingT) {
+		AssertSuccessRate(New(t, short), 3, 0.5, func(a *Asserter) {
+			a.AssertRecv(make(chan int))
+		})
+	})
+
+	assertFails(t, "runs must be positive, got 0", func(t TestingT) {
+		AssertSuccessRate(t, 0, 0.5, func(*Asserter) {})
+	})
+}
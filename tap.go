@@ -0,0 +1,52 @@
+package chantest
+
+// Tap relays values from ch to out, for the consumer under test, and copies
+// each of them to tap, for the test to observe.
+//
+// Values are copied to tap as soon as they're received from ch, before the
+// consumer gets them from out. tap buffers without bound, so not receiving
+// from it never holds up out. out is closed once ch is closed, and tap once
+// every copy has been received from it.
+func Tap[T any](ch <-chan T) (out, tap <-chan T) {
+	outCh, copies := make(chan T), make(chan T)
+	go func() {
+		defer close(copies)
+		defer close(outCh)
+		for v := range ch {
+			copies <- v
+			outCh <- v
+		}
+	}()
+	return outCh, unbounded(copies)
+}
+
+// unbounded returns a channel that relays the values from in, buffering them
+// without bound, so that sends to in never block for long. It's closed once in
+// is closed and every value is relayed.
+func unbounded[T any](in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var queue []T
+		for in != nil || len(queue) > 0 {
+			var send chan T
+			var next T
+			if len(queue) > 0 {
+				send, next = out, queue[0]
+			}
+			select {
+			case v, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				queue = append(queue, v)
+			case send <- next:
+				var zero T
+				queue[0] = zero
+				queue = queue[1:]
+			}
+		}
+	}()
+	return out
+}
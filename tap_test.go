@@ -0,0 +1,33 @@
+package chantest
+
+import "testing"
+
+func TestTap(t *testing.T) {
+	ch := make(chan int)
+	out, tap := Tap(ch)
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			ch <- i
+		}
+		close(ch)
+	}()
+
+	for i := 0; i < 3; i++ {
+		// The tap sees each value in flight, before it's consumed.
+		if got := Recv(t, tap); got != i {
+			t.Fatalf("tapped %v, want %v", got, i)
+		}
+		if got := Recv(t, out); got != i {
+			t.Fatalf("received %v, want %v", got, i)
+		}
+	}
+	Expect(t, func() {
+		if _, ok := <-out; ok {
+			t.Error("out not closed")
+		}
+		if _, ok := <-tap; ok {
+			t.Error("tap not closed")
+		}
+	})
+}
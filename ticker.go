@@ -0,0 +1,91 @@
+package chantest
+
+import (
+	"fmt"
+	"time"
+)
+
+// AssertTicks asserts that n ticks are received from ch, such as a
+// time.Ticker's C, within d as measured by the Asserter's clock, and returns
+// them.
+//
+// If the clock is a FakeClock, AssertTicks advances it, rather than waiting
+// for it, to each due timer in turn until d elapses, so that the ticks of a
+// FakeTicker from it arrive without sleeping through real intervals.
+func AssertTicks(t TestingT, ch <-chan time.Time, n int, d time.Duration, msgAndArgs ...interface{}) []time.Time {
+	a := asserterFor(t)
+	a.t.Helper()
+	var ticks []time.Time
+	fail := func() []time.Time {
+		a.t.Helper()
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("received %d of %d ticks within %v", len(ticks), n, d), msgAndArgs...))
+		return ticks
+	}
+
+	if fc, ok := a.clock.(*FakeClock); ok {
+		deadline := fc.Now().Add(d)
+		for len(ticks) < n {
+			if tick, ok := recvBriefly(a, ch); ok {
+				ticks = append(ticks, tick)
+				continue
+			}
+			at, ok := fc.next()
+			if !ok || at.After(deadline) {
+				return fail()
+			}
+			fc.Advance(at.Sub(fc.Now()))
+		}
+		return ticks
+	}
+
+	timeout, stop := startTimer(a.clock, d)
+	defer stop()
+	for len(ticks) < n {
+		select {
+		case tick := <-ch:
+			ticks = append(ticks, tick)
+		case <-timeout:
+			return fail()
+		}
+	}
+	return ticks
+}
+
+// AssertNoTick asserts that no tick is received from ch for the Asserter's
+// Before duration. If its clock is a FakeClock, AssertNoTick advances it by
+// that much instead of waiting, and then checks for a tick.
+func AssertNoTick(t TestingT, ch <-chan time.Time, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	if tick, ok := waitTick(a, ch, time.Duration(a.d)); ok {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("unexpected tick at %v", tick), msgAndArgs...))
+	}
+}
+
+// waitTick waits for d on the Asserter's clock for a tick from ch, or, if
+// the clock is a FakeClock, advances it by d and then checks for one.
+func waitTick(a *Asserter, ch <-chan time.Time, d time.Duration) (time.Time, bool) {
+	if fc, ok := a.clock.(*FakeClock); ok {
+		fc.Advance(d)
+		return recvBriefly(a, ch)
+	}
+	timeout, stop := startTimer(a.clock, d)
+	defer stop()
+	select {
+	case tick := <-ch:
+		return tick, true
+	case <-timeout:
+		return time.Time{}, false
+	}
+}
+
+// recvBriefly receives a tick from ch if one arrives within the Asserter's
+// poll interval, for ticks relayed by a goroutine after the clock advances.
+func recvBriefly(a *Asserter, ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case tick := <-ch:
+		return tick, true
+	case <-time.After(a.pollInterval()):
+		return time.Time{}, false
+	}
+}
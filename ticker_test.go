@@ -0,0 +1,45 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssertTicks(t *testing.T) {
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	AssertTicks(t, ticker.C, 3, time.Second)
+	assertFails(t, "received 0 of 1 ticks within 1ms", func(t TestingT) {
+		AssertTicks(t, time.NewTicker(time.Hour).C, 1, time.Millisecond)
+	})
+	AssertNoTick(New(t, short), time.NewTicker(time.Hour).C)
+	assertFails(t, "unexpected tick at ", func(t TestingT) {
+		AssertNoTick(t, ticker.C)
+	})
+}
+
+func TestAssertTicksFakeClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewFakeClock(start)
+	a := New(t, WithClock(clock), Before(time.Minute))
+	ticker := clock.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	AssertNoTick(a, ticker.C)
+	ticks := AssertTicks(a, ticker.C, 3, 3*time.Hour)
+	for i, tick := range ticks {
+		if want := start.Add(time.Duration(i+1) * time.Hour); !tick.Equal(want) {
+			t.Fatalf("tick %d at %v, want %v", i, tick, want)
+		}
+	}
+	assertFails(t, "received 0 of 1 ticks within 59m0s", func(t TestingT) {
+		AssertTicks(New(t, a), ticker.C, 1, 59*time.Minute)
+	})
+
+	ticker.Reset(time.Minute)
+	assertFails(t, "unexpected tick at ", func(t TestingT) {
+		AssertNoTick(New(t, a), ticker.C)
+	})
+	ticker.Stop()
+	AssertNoTick(a, ticker.C)
+}
@@ -0,0 +1,60 @@
+package chantest
+
+import (
+	"fmt"
+	"time"
+)
+
+// AssertFiresAfter asserts that ch, such as a timer's C, fires once d has
+// elapsed since start, on the Asserter's clock, not before, and no later than
+// its Before duration after that. start is typically taken from the clock
+// right before the code under test creates the timer. It returns the time ch
+// fires with, which tells whether it fired early, so racing the timer's own
+// deadline doesn't.
+//
+// If the clock is a FakeClock, AssertFiresAfter advances it, rather than
+// waiting for it, to just short of the deadline and then the rest of the way.
+func AssertFiresAfter(t TestingT, ch <-chan time.Time, start time.Time, d time.Duration, msgAndArgs ...interface{}) time.Time {
+	a := asserterFor(t)
+	a.t.Helper()
+	deadline := start.Add(d)
+	early := func(at time.Time) time.Time {
+		a.t.Helper()
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("fired at %v, %v after start, before %v elapsed", at, at.Sub(start), d), msgAndArgs...))
+		return at
+	}
+
+	remaining := deadline.Sub(a.clock.Now())
+	late := remaining + time.Duration(a.d)
+	if _, ok := a.clock.(*FakeClock); ok {
+		if remaining > 1 {
+			if at, ok := waitTick(a, ch, remaining-1); ok {
+				return early(at)
+			}
+			remaining = 1
+		}
+		late = 0
+		if remaining > 0 {
+			late = remaining
+		}
+	}
+	at, ok := waitTick(a, ch, late)
+	switch {
+	case !ok:
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("timeout waiting for channel to fire after %v", d), msgAndArgs...))
+	case at.Before(deadline):
+		return early(at)
+	}
+	return at
+}
+
+// AssertNotFires asserts that ch, such as a timer's C, doesn't fire for the
+// Asserter's Before duration, and, like AssertNoTick, advances a FakeClock by
+// that much instead of waiting.
+func AssertNotFires(t TestingT, ch <-chan time.Time, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	if at, ok := waitTick(a, ch, time.Duration(a.d)); ok {
+		a.t.Fatal(defaultOrCustomMessage(fmt.Sprintf("unexpectedly fired at %v", at), msgAndArgs...))
+	}
+}
@@ -0,0 +1,63 @@
+package chantest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssertFiresAfter(t *testing.T) {
+	start := time.Now()
+	AssertFiresAfter(t, time.NewTimer(50*time.Millisecond).C, start, 50*time.Millisecond)
+	start = time.Now()
+	timer := time.NewTimer(20 * time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	AssertFiresAfter(t, timer.C, start, 20*time.Millisecond)
+
+	assertFails(t, "before 1h0m0s elapsed", func(t TestingT) {
+		start := time.Now()
+		AssertFiresAfter(t, time.After(time.Millisecond), start, time.Hour)
+	})
+	assertFails(t, "timeout waiting for channel to fire after 1ms", func(t TestingT) {
+		start := time.Now()
+		AssertFiresAfter(New(t, short), time.After(time.Hour), start, time.Millisecond)
+	})
+	AssertNotFires(New(t, short), time.After(time.Hour))
+	assertFails(t, "unexpectedly fired at ", func(t TestingT) {
+		AssertNotFires(t, time.After(time.Millisecond))
+	})
+}
+
+func TestAssertFiresAfterFakeClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewFakeClock(start)
+	a := New(t, WithClock(clock), Before(time.Minute))
+
+	if at := AssertFiresAfter(a, clock.After(time.Hour), clock.Now(), time.Hour); !at.Equal(start.Add(time.Hour)) {
+		t.Fatalf("fired at %v", at)
+	}
+	assertFails(t, "before 1h0m0s elapsed", func(t TestingT) {
+		AssertFiresAfter(New(t, a), clock.After(time.Minute), clock.Now(), time.Hour)
+	})
+	assertFails(t, "timeout waiting for channel to fire after 1m0s", func(t TestingT) {
+		AssertFiresAfter(New(t, a), clock.After(time.Hour), clock.Now(), time.Minute)
+	})
+
+	timeout := clock.After(time.Hour)
+	AssertNotFires(a, timeout)
+	if !clock.FireNow(timeout) {
+		t.Fatal("timer wasn't pending")
+	}
+	AssertRecv(t, timeout)
+	if clock.FireNow(timeout) {
+		t.Fatal("fired timer still pending")
+	}
+
+	ticker := clock.NewTicker(time.Hour)
+	defer ticker.Stop()
+	now := clock.Now()
+	clock.FireNow(ticker.C)
+	if at := AssertRecv(t, ticker.C); !at.(time.Time).Equal(now) {
+		t.Fatalf("ticked at %v", at)
+	}
+	AssertFiresAfter(a, ticker.C, now, time.Hour)
+}
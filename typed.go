@@ -0,0 +1,178 @@
+package chantest
+
+import "fmt"
+
+// The functions in this file are typed counterparts of AssertRecv,
+// AssertNoRecv, AssertSend and AssertNoSend. They use plain selects instead of
+// reflect.Select, so they're faster and don't box values; see BenchmarkRecv,
+// where most of what's left is managing the timeout's timer. To wait for
+// something other than Default, pass an Asserter as t.
+//
+// Given an Asserter from New, and no msgAndArgs, they don't allocate unless
+// they fail, so they can be used in testing.B loops without distorting the
+// measured allocations.
+
+// Recv asserts that a value is quickly received from ch, and returns it.
+func Recv[T any](t TestingT, ch <-chan T, msgAndArgs ...interface{}) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	v, ok := recv(a, ch)
+	if !ok {
+		var attempts string
+		if v, ok, attempts = retry(a, func(a *Asserter) (T, bool) { return recv(a, ch) }); !ok {
+			a.t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...) + attempts)
+		}
+	}
+	return v
+}
+
+// NoRecv asserts that nothing is received from ch for a very short period of
+// time. If something is, it's returned.
+func NoRecv[T any](t TestingT, ch <-chan T, msgAndArgs ...interface{}) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	v, ok := recv(a, ch)
+	if ok {
+		a.t.Fatal(defaultOrCustomMessage("unexpected channel receive", msgAndArgs...))
+	}
+	return v
+}
+
+// RecvAny asserts that a value is quickly received from any of chs, and
+// returns the index of the channel and the value. Unlike the functions above,
+// it's built on reflect.Select, as the number of channels varies.
+func RecvAny[T any](t TestingT, chs ...<-chan T) (index int, v T) {
+	a := asserterFor(t)
+	a.t.Helper()
+	chans := make([]interface{}, len(chs))
+	for i, ch := range chs {
+		chans[i] = ch
+	}
+	i, recv := a.AssertRecvAny(chans...)
+	if recv == nil {
+		return i, v
+	}
+	return i, recv.(T)
+}
+
+// RecvEach asserts that a value is quickly received from each of chs, and
+// returns the values, in the order of chs. Like RecvAny, it's built on
+// reflect.Select.
+func RecvEach[T any](t TestingT, chs ...<-chan T) []T {
+	a := asserterFor(t)
+	a.t.Helper()
+	chans := make([]interface{}, len(chs))
+	for i, ch := range chs {
+		chans[i] = ch
+	}
+	values := make([]T, len(chs))
+	for i, v := range a.AssertRecvEach(chans...) {
+		if v != nil {
+			values[i] = v.(T)
+		}
+	}
+	return values
+}
+
+// Send asserts that v is quickly sent to ch.
+func Send[T any](t TestingT, ch chan<- T, v T, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	if send(a, ch, v) {
+		return
+	}
+	if _, ok, attempts := retry(a, func(a *Asserter) (struct{}, bool) { return struct{}{}, send(a, ch, v) }); !ok {
+		a.t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...) + attempts)
+	}
+}
+
+// NoSend asserts that v is not sent to ch for a very short period of time.
+func NoSend[T any](t TestingT, ch chan<- T, v T, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	if send(a, ch, v) {
+		a.t.Fatal(defaultOrCustomMessage("unexpected channel receive", msgAndArgs...))
+	}
+}
+
+// AssertRecvBefore asserts that a value is quickly received from ch before
+// anything is received from other, and returns it. If a value is received from
+// other first, it's consumed, and reported in the failure. If both already
+// have values ready, ch's is taken, as there's no telling which came first.
+//
+// Useful for requirements like an ack preceding a result, where the result
+// arriving first is the bug.
+func AssertRecvBefore[T, U any](t TestingT, ch <-chan T, other <-chan U, msgAndArgs ...interface{}) T {
+	a := asserterFor(t)
+	a.t.Helper()
+	select {
+	case v, ok := <-ch:
+		if ok {
+			a.matched(ch, v)
+		}
+		return v
+	default:
+	}
+	tm := a.startTimer()
+	defer tm.stop()
+	var zero T
+	select {
+	case v, ok := <-ch:
+		if ok {
+			a.matched(ch, v)
+		}
+		return v
+	case v, ok := <-other:
+		msg := fmt.Sprintf("received %#v from other channel first", v)
+		if !ok {
+			msg = "other channel closed first"
+		}
+		a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+		return zero
+	case <-tm.C:
+		a.t.Fatal(defaultOrCustomMessage("timeout waiting for channel send or receive", msgAndArgs...))
+		return zero
+	}
+}
+
+func recv[T any](a *Asserter, ch <-chan T) (T, bool) {
+	tm := a.startTimer()
+	defer tm.stop()
+	select {
+	case v, ok := <-ch:
+		if ok {
+			a.matched(ch, v)
+		}
+		return v, true
+	case <-tm.C:
+		var zero T
+		return zero, false
+	}
+}
+
+func send[T any](a *Asserter, ch chan<- T, v T) bool {
+	tm := a.startTimer()
+	defer tm.stop()
+	select {
+	case ch <- v:
+		return true
+	case <-tm.C:
+		return false
+	}
+}
+
+// recvOrClose is like recv, but also reports whether ch was closed, as ok, as
+// opposed to whether anything was received before the timeout.
+func recvOrClose[T any](a *Asserter, ch <-chan T) (v T, ok, received bool) {
+	tm := a.startTimer()
+	defer tm.stop()
+	select {
+	case v, ok := <-ch:
+		if ok {
+			a.matched(ch, v)
+		}
+		return v, ok, true
+	case <-tm.C:
+		return v, false, false
+	}
+}
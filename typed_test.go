@@ -0,0 +1,111 @@
+package chantest
+
+import "testing"
+
+func TestTyped(t *testing.T) {
+	ch := make(chan int, 1)
+	assertPasses(t, func(t TestingT) {
+		Send(t, ch, 42)
+		if got := Recv(t, ch); got != 42 {
+			t.Fatal("unexpected value", got)
+		}
+		a := New(t, short)
+		NoRecv(a, ch)
+		Send(a, ch, 43)
+		NoSend(a, ch, 44)
+	})
+	assertFails(t, "unexpected channel receive", func(t TestingT) {
+		NoRecv(t, ch)
+	})
+	assertFails(t, "timeout waiting for channel send or receive", func(t TestingT) {
+		Recv(New(t, short), ch)
+	})
+	assertFails(t, "no value from ch", func(t TestingT) {
+		Recv(New(t, short), ch, "no value from %s", "ch")
+	})
+}
+
+func TestAssertRecvBefore(t *testing.T) {
+	acks, results := make(chan int, 1), make(chan string, 1)
+	assertPasses(t, func(t TestingT) {
+		acks <- 1
+		results <- "done"
+		if got := AssertRecvBefore(t, acks, results); got != 1 {
+			t.Fatal("unexpected value", got)
+		}
+	})
+	assertFails(t, `received "done" from other channel first`, func(t TestingT) {
+		AssertRecvBefore(t, acks, results)
+	})
+	close(results)
+	assertFails(t, "other channel closed first", func(t TestingT) {
+		AssertRecvBefore(t, acks, results)
+	})
+	assertFails(t, "timeout waiting for channel send or receive", func(t TestingT) {
+		AssertRecvBefore(New(t, short), acks, make(chan string))
+	})
+}
+
+func TestTypedDoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("timers aren't reliably reused with the race detector")
+	}
+	a := New(t)
+	ch := make(chan int, 1)
+	allocs := testing.AllocsPerRun(100, func() {
+		Send(a, ch, 1)
+		Recv(a, ch)
+	})
+	if allocs != 0 {
+		t.Fatalf("typed assertions made %v allocations per run", allocs)
+	}
+}
+
+func BenchmarkRecv(b *testing.B) {
+	b.Run("typed", func(b *testing.B) {
+		a := New(b)
+		ch := make(chan int, 1)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ch <- i
+			Recv(a, ch)
+		}
+	})
+	b.Run("reflect", func(b *testing.B) {
+		a := New(b)
+		ch := make(chan int, 1)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ch <- i
+			a.AssertRecv(ch)
+		}
+	})
+}
+
+func TestRecvAny(t *testing.T) {
+	a, b := make(chan int, 1), make(chan int, 1)
+	b <- 2
+	if i, v := RecvAny[int](t, a, b); i != 1 || v != 2 {
+		t.Fatalf("got %d, %d", i, v)
+	}
+	a <- 1
+	if i, v := AssertRecvAny(t, a, b, make(chan string)); i != 0 || v != 1 {
+		t.Fatalf("got %d, %v", i, v)
+	}
+	assertFails(t, "timeout waiting for a receive from any of 2 channels", func(t TestingT) {
+		RecvAny[int](New(t, short), a, b)
+	})
+}
+
+func TestRecvEach(t *testing.T) {
+	a, b, c := make(chan int, 1), make(chan int, 1), make(chan int, 1)
+	b <- 2
+	a <- 1
+	if got := RecvEach[int](t, a, b); got[0] != 1 || got[1] != 2 {
+		t.Fatalf("got %v", got)
+	}
+	b <- 3
+	assertFails(t, "timeout waiting for receives; channels [0 2] were silent", func(t TestingT) {
+		AssertRecvEach(New(t, short), a, b, c)
+	})
+}
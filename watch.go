@@ -0,0 +1,108 @@
+package chantest
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A Watcher receives every value from a channel as soon as it's sent, for
+// assertions to check later, so that they don't miss values sent before they
+// start waiting.
+type Watcher[T any] struct {
+	stop chan struct{}
+
+	mu     sync.Mutex
+	values []T
+	// changed is closed and replaced whenever values changes.
+	changed chan struct{}
+}
+
+// Watch starts receiving from ch into a Watcher, until ch is closed or, if t
+// has a Cleanup method, like *testing.T, the test ends. If the Asserter is
+// Strict, every value received counts as matched by an assertion.
+func Watch[T any](t TestingT, ch <-chan T) *Watcher[T] {
+	a := asserterFor(t)
+	a.t.Helper()
+	w := &Watcher[T]{stop: make(chan struct{}), changed: make(chan struct{})}
+	go func() {
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					return
+				}
+				a.matched(ch, v)
+				w.update(func() { w.values = append(w.values, v) })
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+	if c, ok := a.t.(cleanuper); ok {
+		c.Cleanup(func() { close(w.stop) })
+	}
+	return w
+}
+
+func (w *Watcher[T]) update(f func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	f()
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// Values returns the values received so far.
+func (w *Watcher[T]) Values() []T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]T(nil), w.values...)
+}
+
+// AssertSaw asserts that v very quickly is, or gets, received, as per
+// reflect.DeepEqual unless otherwise set with WithEqual.
+func (w *Watcher[T]) AssertSaw(t TestingT, v T, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	w.assertEventually(a, func(values []T) (bool, string) {
+		for _, got := range values {
+			if a.equals(got, v) {
+				return true, ""
+			}
+		}
+		return false, fmt.Sprintf("didn't see %#v; saw %#v", v, values)
+	}, msgAndArgs...)
+}
+
+// AssertCount asserts that the number of values received very quickly is, or
+// gets to, n.
+func (w *Watcher[T]) AssertCount(t TestingT, n int, msgAndArgs ...interface{}) {
+	a := asserterFor(t)
+	a.t.Helper()
+	w.assertEventually(a, func(values []T) (bool, string) {
+		return len(values) == n, fmt.Sprintf("saw %d values, want %d: %#v", len(values), n, values)
+	}, msgAndArgs...)
+}
+
+// assertEventually fails unless check passes on the values received before
+// the Asserter's timeout. check also returns the failure message.
+func (w *Watcher[T]) assertEventually(a *Asserter, check func([]T) (bool, string), msgAndArgs ...interface{}) {
+	a.t.Helper()
+	timeout, stop := a.timeout()
+	defer stop()
+	for {
+		w.mu.Lock()
+		values, changed := w.values, w.changed
+		w.mu.Unlock()
+		ok, msg := check(values)
+		if ok {
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			a.t.Fatal(defaultOrCustomMessage(msg, msgAndArgs...))
+			return
+		}
+	}
+}
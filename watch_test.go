@@ -0,0 +1,28 @@
+package chantest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWatch(t *testing.T) {
+	ch := make(chan string)
+	w := Watch(t, ch)
+	ch <- "a"
+	ch <- "b"
+	w.AssertSaw(t, "b")
+	w.AssertCount(t, 2)
+	go func() { ch <- "c" }()
+	w.AssertSaw(t, "c")
+	if got := w.Values(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("got %v", got)
+	}
+
+	assertFails(t, `didn't see "d"; saw []string{"a", "b", "c"}`, func(t TestingT) {
+		w.AssertSaw(New(t, short), "d")
+	})
+	assertFails(t, `saw 3 values, want 2: []string{"a", "b", "c"}`, func(t TestingT) {
+		w.AssertCount(New(t, short), 2)
+	})
+	close(ch)
+}
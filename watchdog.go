@@ -0,0 +1,86 @@
+package chantest
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watchdog panics if the test is still running after budget, with a dump of
+// the state of the channels registered with t, if it's an Asserter, and of
+// every goroutine's stack. The dump is also written to os.Stderr, in case the
+// panic is recovered.
+//
+// Set budget shorter than go test's -timeout, whose own panic only has the
+// stacks, to find out where a hung test is stuck. The budget is measured on
+// the wall clock even if t is an Asserter with a FakeClock, which nobody
+// advances while the test hangs.
+//
+// Call the returned function, or let t's Cleanup, if it has one, do it, once
+// the test is done.
+func Watchdog(t TestingT, budget time.Duration) (stop func()) {
+	return watchdog(t, budget, func(dump string) {
+		fmt.Fprint(os.Stderr, dump)
+		panic(dump)
+	})
+}
+
+func watchdog(t TestingT, budget time.Duration, fire func(dump string)) (stop func()) {
+	a := asserterFor(t)
+	timer := time.NewTimer(budget)
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-timer.C:
+			fire(watchdogDump(a, budget))
+		case <-stopped:
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			timer.Stop()
+			close(stopped)
+		})
+	}
+	if c, ok := a.t.(cleanuper); ok {
+		c.Cleanup(stop)
+	}
+	return stop
+}
+
+func watchdogDump(a *Asserter, budget time.Duration) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "chantest: test still running after watchdog budget of %v\n", budget)
+	if names, activities := a.registry.snapshot(); len(names) > 0 {
+		b.WriteString("\nchannels:\n")
+		for _, name := range names {
+			act := activities[name]
+			last := "never active"
+			if !act.last.IsZero() {
+				last = "last active " + act.last.Format(time.RFC3339Nano)
+			}
+			fmt.Fprintf(&b, "\t%s: %s; %s\n", name, act.state, last)
+		}
+	}
+	b.WriteString("\ngoroutines:\n")
+	b.Write(allStacks())
+	return b.String()
+}
+
+// allStacks returns the stacks of all goroutines, as formatted by
+// runtime.Stack.
+func allStacks() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
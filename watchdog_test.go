@@ -0,0 +1,47 @@
+package chantest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	a := New(t, WithClock(clock))
+	stuck := Instrument(make(chan int), WithClock(clock))
+	a.Register("stuck", stuck)
+	stuck.In() <- 1
+
+	// The FakeClock standing still doesn't hold the watchdog back.
+	dumps := make(chan string, 1)
+	watchdog(a, 20*time.Millisecond, func(dump string) { dumps <- dump })
+
+	dump := Recv(t, dumps)
+	for _, want := range []string{
+		"test still running after watchdog budget of 20ms",
+		"stuck: 1 sent, 0 received, open; last active 1970-01-01",
+		"goroutines:\ngoroutine ",
+		"chantest.(*Instrumented[...]).relayOut",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Fatalf("dump doesn't contain %q:\n%s", want, dump)
+		}
+	}
+}
+
+func TestWatchdogStopped(t *testing.T) {
+	dumps := make(chan string, 1)
+	stop := watchdog(t, 10*time.Millisecond, func(dump string) { dumps <- dump })
+	stop()
+	stop()
+	NoRecv(New(t, Before(30*time.Millisecond)), dumps)
+
+	t.Run("cleanup", func(t *testing.T) {
+		Watchdog(t, time.Minute)
+	})
+	t.Run("cleanup with Asserter", func(t *testing.T) {
+		watchdog(New(t), 10*time.Millisecond, func(dump string) { dumps <- dump })
+	})
+	NoRecv(New(t, Before(30*time.Millisecond)), dumps)
+}
@@ -0,0 +1,126 @@
+package chantest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A WorkerPool is a harness for a pool of workers that receive tasks from
+// Tasks and send a result for each to Results.
+type WorkerPool[Task, Result any] struct {
+	Tasks   chan<- Task
+	Results <-chan Result
+	// Worker, if set, returns the worker that produced a result, for
+	// AssertNoneStarved.
+	Worker func(Result) string
+}
+
+// Run sends each of tasks to Tasks, while receiving from Results, and returns
+// the results once there's one for each task. It fails the test if no task is
+// taken, or no result arrives, before the timeout.
+func (p WorkerPool[Task, Result]) Run(t TestingT, tasks []Task) []Result {
+	a := asserterFor(t)
+	a.t.Helper()
+
+	submitted := make(chan int, 1)
+	go func() {
+		for i, task := range tasks {
+			if !send(a, p.Tasks, task) {
+				submitted <- i
+				return
+			}
+		}
+		submitted <- len(tasks)
+	}()
+
+	results := make([]Result, 0, len(tasks))
+	for len(results) < len(tasks) {
+		v, ok, received := recvOrClose(a, p.Results)
+		if !received || !ok {
+			break
+		}
+		results = append(results, v)
+	}
+
+	if n := <-submitted; n < len(tasks) {
+		a.t.Fatal(fmt.Sprintf("timeout submitting task #%d of %d; %d results received", n, len(tasks), len(results)))
+		return results
+	}
+	if len(results) < len(tasks) {
+		a.t.Fatal(fmt.Sprintf("%d of %d results received", len(results), len(tasks)))
+	}
+	return results
+}
+
+// AssertNoneStarved asserts that each of workers produced at least one of
+// results, as told by Worker.
+func (p WorkerPool[Task, Result]) AssertNoneStarved(t TestingT, results []Result, workers ...string) {
+	t = unwrap(t)
+	t.Helper()
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[p.Worker(r)]++
+	}
+	var starved []string
+	for _, w := range workers {
+		if counts[w] == 0 {
+			starved = append(starved, w)
+		}
+	}
+	if len(starved) > 0 {
+		t.Fatal(fmt.Sprintf("starved workers: %s; results per worker: %s", strings.Join(starved, ", "), countList(counts)))
+	}
+}
+
+func countList(counts map[string]int) string {
+	var list []string
+	for k, n := range counts {
+		list = append(list, fmt.Sprintf("%s: %d", k, n))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+// A Concurrency tracks how many goroutines are running a section of code at
+// once, such as a worker's task handling.
+type Concurrency struct {
+	mu       sync.Mutex
+	cur, max int
+}
+
+// Enter marks a goroutine entering the section, and returns the function to
+// call on leaving it.
+//
+//	defer c.Enter()()
+func (c *Concurrency) Enter() (leave func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cur++
+	if c.cur > c.max {
+		c.max = c.cur
+	}
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.cur--
+	}
+}
+
+// Max returns the most goroutines that have been in the section at once.
+func (c *Concurrency) Max() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.max
+}
+
+// AssertMax asserts that no more than n goroutines have been in the section
+// at once.
+func (c *Concurrency) AssertMax(t TestingT, n int, msgAndArgs ...interface{}) {
+	t = unwrap(t)
+	t.Helper()
+	if max := c.Max(); max > n {
+		t.Fatal(defaultOrCustomMessage(fmt.Sprintf("%d goroutines in section at once, want at most %d", max, n), msgAndArgs...))
+	}
+}
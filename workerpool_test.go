@@ -0,0 +1,66 @@
+package chantest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type poolResult struct {
+	worker string
+	task   int
+}
+
+// startPool starts n workers, each holding tasks for a moment so that they
+// overlap.
+func startPool(n int, c *Concurrency) WorkerPool[int, poolResult] {
+	tasks, results := make(chan int), make(chan poolResult)
+	for i := 0; i < n; i++ {
+		worker := fmt.Sprint("w", i)
+		go func() {
+			for task := range tasks {
+				leave := c.Enter()
+				time.Sleep(time.Millisecond)
+				leave()
+				results <- poolResult{worker, task}
+			}
+		}()
+	}
+	return WorkerPool[int, poolResult]{
+		Tasks:   tasks,
+		Results: results,
+		Worker:  func(r poolResult) string { return r.worker },
+	}
+}
+
+func TestWorkerPool(t *testing.T) {
+	var c Concurrency
+	pool := startPool(3, &c)
+	results := pool.Run(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	if len(results) != 9 {
+		t.Fatalf("got %d results", len(results))
+	}
+	c.AssertMax(t, 3)
+	assertFails(t, "4 goroutines in section at once, want at most 2", func(t TestingT) {
+		var c Concurrency
+		for i := 0; i < 4; i++ {
+			c.Enter()
+		}
+		c.AssertMax(t, 2)
+	})
+
+	assertFails(t, "starved workers: w2; results per worker: w0: 1, w1: 2", func(t TestingT) {
+		pool.AssertNoneStarved(t, []poolResult{{"w0", 1}, {"w1", 2}, {"w1", 3}}, "w0", "w1", "w2")
+	})
+}
+
+func TestWorkerPoolFailures(t *testing.T) {
+	assertFails(t, "timeout submitting task #0 of 1; 0 results received", func(t TestingT) {
+		WorkerPool[int, int]{Tasks: make(chan int), Results: make(chan int)}.Run(New(t, short), []int{1})
+	})
+	assertFails(t, "1 of 2 results received", func(t TestingT) {
+		tasks, results := make(chan int, 2), make(chan int, 1)
+		results <- 1
+		WorkerPool[int, int]{Tasks: tasks, Results: results}.Run(New(t, short), []int{1, 2})
+	})
+}